@@ -0,0 +1,40 @@
+package api
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/SKevo18/server_updater/manifest"
+)
+
+const BungeeCordJenkinsUrl = "https://ci.md-5.net"
+
+// GetBungeeCordServerJar resolves the BungeeCord proxy jar from its Jenkins.
+// BungeeCord builds aren't tied to a Minecraft version, the latest build supports all of them.
+func GetBungeeCordServerJar(server manifest.Server) (*ServerJar, error) {
+	build := server.LoaderVersion
+	if build == "@latest" {
+		build = "lastSuccessfulBuild"
+	}
+
+	jenkinsBuild, err := GetJenkinsBuild(BungeeCordJenkinsUrl, "BungeeCord", build)
+	if err != nil {
+		return nil, fmt.Errorf("bungeecord build '%s' not found: %w", server.LoaderVersion, err)
+	}
+
+	artifact := FindJenkinsArtifact(jenkinsBuild.Artifacts, "BungeeCord.jar")
+	if artifact == nil {
+		return nil, fmt.Errorf("bungeecord build %d has no proxy jar", jenkinsBuild.Number)
+	}
+
+	buildNumber := strconv.Itoa(jenkinsBuild.Number)
+	return &ServerJar{
+		Dependency: &manifest.Dependency{
+			ProjectId:   "bungeecord",
+			Version:     buildNumber,
+			FileName:    fmt.Sprintf("BungeeCord-%s.jar", buildNumber),
+			DownloadUrl: jenkinsBuild.ArtifactUrl(artifact),
+			SaveAs:      server.CanonicalLoaderFile(buildNumber),
+		},
+	}, nil
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+)
+
+// HashFile returns the hex encoded SHA-256 hash of the file at path
+func HashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// VerifyFile checks the file at path against a hex encoded hash.
+// The algorithm (MD5, SHA-1, SHA-256 or SHA-512) is inferred from the length of the hash.
+func VerifyFile(path, expectedHash string) error {
+	expectedHash = strings.ToLower(strings.TrimSpace(expectedHash))
+
+	var h hash.Hash
+	switch len(expectedHash) {
+	case md5.Size * 2:
+		h = md5.New()
+	case sha1.Size * 2:
+		h = sha1.New()
+	case sha256.Size * 2:
+		h = sha256.New()
+	case sha512.Size * 2:
+		h = sha512.New()
+	default:
+		return fmt.Errorf("unsupported hash format: %s", expectedHash)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(h, file); err != nil {
+		return err
+	}
+
+	if actualHash := hex.EncodeToString(h.Sum(nil)); actualHash != expectedHash {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", path, expectedHash, actualHash)
+	}
+	return nil
+}
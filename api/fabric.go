@@ -0,0 +1,91 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/SKevo18/server_updater/manifest"
+)
+
+const FabricMetaUrl = "https://meta.fabricmc.net/v2"
+
+// GetFabricGameVersions gets all Minecraft versions supported by Fabric, newest first
+func GetFabricGameVersions() ([]FabricGameVersion, error) {
+	var versions []FabricGameVersion
+	err := get(fmt.Sprintf("%s/versions/game", FabricMetaUrl), &versions)
+	return versions, err
+}
+
+// GetFabricLoaderVersions gets all Fabric loader versions available for a Minecraft version, newest first
+func GetFabricLoaderVersions(minecraftVersion string) ([]FabricLoaderVersion, error) {
+	var versions []FabricLoaderVersion
+	err := get(fmt.Sprintf("%s/versions/loader/%s", FabricMetaUrl, minecraftVersion), &versions)
+	return versions, err
+}
+
+// GetFabricInstallerVersions gets all Fabric installer versions, newest first
+func GetFabricInstallerVersions() ([]FabricInstallerVersion, error) {
+	var versions []FabricInstallerVersion
+	err := get(fmt.Sprintf("%s/versions/installer", FabricMetaUrl), &versions)
+	return versions, err
+}
+
+// ResolveFabricMinecraftVersion resolves "@latest" to the newest Minecraft version supported by Fabric,
+// and "@latest-stable" to the newest release
+func ResolveFabricMinecraftVersion(wantedVersion string) (string, error) {
+	versions, err := GetFabricGameVersions()
+	if err != nil {
+		return "", err
+	}
+
+	for _, v := range versions {
+		if v.Stable || wantedVersion != "@latest-stable" {
+			return v.Version, nil
+		}
+	}
+	return "", fmt.Errorf("no fabric game versions found")
+}
+
+// GetFabricServerJar resolves the Fabric server launcher jar
+func GetFabricServerJar(server manifest.Server) (*ServerJar, error) {
+	loaderVersions, err := GetFabricLoaderVersions(server.MinecraftVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fabric loader versions for %s: %w", server.MinecraftVersion, err)
+	}
+
+	var loaderVersion string
+	for _, v := range loaderVersions {
+		if (server.LoaderVersion == "@latest" && v.Loader.Stable) || v.Loader.Version == server.LoaderVersion {
+			loaderVersion = v.Loader.Version
+			break
+		}
+	}
+	if loaderVersion == "" {
+		return nil, fmt.Errorf("fabric loader '%s' not found for Minecraft %s", server.LoaderVersion, server.MinecraftVersion)
+	}
+
+	installerVersions, err := GetFabricInstallerVersions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fabric installer versions: %w", err)
+	}
+
+	var installerVersion string
+	for _, v := range installerVersions {
+		if v.Stable {
+			installerVersion = v.Version
+			break
+		}
+	}
+	if installerVersion == "" {
+		return nil, fmt.Errorf("no stable fabric installer found")
+	}
+
+	return &ServerJar{
+		Dependency: &manifest.Dependency{
+			ProjectId:   "fabric",
+			Version:     loaderVersion,
+			FileName:    fmt.Sprintf("fabric-server-mc.%s-loader.%s-launcher.%s.jar", server.MinecraftVersion, loaderVersion, installerVersion),
+			DownloadUrl: fmt.Sprintf("%s/versions/loader/%s/%s/%s/server/jar", FabricMetaUrl, server.MinecraftVersion, loaderVersion, installerVersion),
+			SaveAs:      server.CanonicalLoaderFile(loaderVersion),
+		},
+	}, nil
+}
@@ -0,0 +1,23 @@
+package api
+
+type FabricGameVersion struct {
+	Version string `json:"version"`
+	Stable  bool   `json:"stable"`
+}
+
+type FabricLoaderVersion struct {
+	Loader struct {
+		Separator string `json:"separator"`
+		Build     int    `json:"build"`
+		Maven     string `json:"maven"`
+		Version   string `json:"version"`
+		Stable    bool   `json:"stable"`
+	} `json:"loader"`
+}
+
+type FabricInstallerVersion struct {
+	URL     string `json:"url"`
+	Maven   string `json:"maven"`
+	Version string `json:"version"`
+	Stable  bool   `json:"stable"`
+}
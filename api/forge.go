@@ -0,0 +1,207 @@
+package api
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/SKevo18/server_updater/manifest"
+	log "github.com/gwillem/go-simplelog"
+)
+
+const (
+	ForgeMavenUrl    = "https://maven.minecraftforge.net"
+	NeoForgeMavenUrl = "https://maven.neoforged.net/releases"
+)
+
+// GetForgeServerJar resolves the Forge installer. Forge versions are prefixed with
+// the Minecraft version in its Maven metadata (e.g. "1.21.7-57.0.2").
+func GetForgeServerJar(server manifest.Server) (*ServerJar, error) {
+	metadata, err := GetMavenMetadata(ForgeMavenUrl, "net.minecraftforge", "forge")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get forge versions: %w", err)
+	}
+
+	var loaderVersions []string
+	for _, v := range metadata.Versioning.Versions {
+		if loaderVersion, ok := strings.CutPrefix(v, server.MinecraftVersion+"-"); ok {
+			loaderVersions = append(loaderVersions, loaderVersion)
+		}
+	}
+
+	loaderVersion := resolveLoaderVersion(loaderVersions, server.LoaderVersion)
+	if loaderVersion == "" {
+		return nil, fmt.Errorf("forge '%s' not found for Minecraft %s", server.LoaderVersion, server.MinecraftVersion)
+	}
+
+	return mavenServerInstaller(ForgeMavenUrl, "net.minecraftforge", "forge", server.MinecraftVersion+"-"+loaderVersion, loaderVersion), nil
+}
+
+// GetNeoForgeServerJar resolves the NeoForge installer. NeoForge versions drop the leading "1."
+// of the Minecraft version they target (e.g. "21.7.5" for Minecraft 1.21.7).
+func GetNeoForgeServerJar(server manifest.Server) (*ServerJar, error) {
+	metadata, err := GetMavenMetadata(NeoForgeMavenUrl, "net.neoforged", "neoforge")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get neoforge versions: %w", err)
+	}
+
+	prefix := neoForgeVersionPrefix(server.MinecraftVersion)
+	var loaderVersions []string
+	for _, v := range metadata.Versioning.Versions {
+		if strings.HasPrefix(v, prefix) {
+			loaderVersions = append(loaderVersions, v)
+		}
+	}
+
+	loaderVersion := resolveLoaderVersion(loaderVersions, server.LoaderVersion)
+	if loaderVersion == "" {
+		return nil, fmt.Errorf("neoforge '%s' not found for Minecraft %s", server.LoaderVersion, server.MinecraftVersion)
+	}
+
+	return mavenServerInstaller(NeoForgeMavenUrl, "net.neoforged", "neoforge", loaderVersion, loaderVersion), nil
+}
+
+// ResolveForgeMinecraftVersion resolves "@latest" and "@latest-stable" to the newest Minecraft version supported by Forge
+func ResolveForgeMinecraftVersion(wantedVersion string) (string, error) {
+	metadata, err := GetMavenMetadata(ForgeMavenUrl, "net.minecraftforge", "forge")
+	if err != nil {
+		return "", err
+	}
+
+	loaderVersions := make(map[string][]string)
+	for _, v := range metadata.Versioning.Versions {
+		if minecraftVersion, loaderVersion, ok := strings.Cut(v, "-"); ok {
+			loaderVersions[minecraftVersion] = append(loaderVersions[minecraftVersion], loaderVersion)
+		}
+	}
+	return resolveLatestMinecraftVersion(loaderVersions, wantedVersion)
+}
+
+// ResolveNeoForgeMinecraftVersion resolves "@latest" and "@latest-stable" to the newest Minecraft version supported by NeoForge
+func ResolveNeoForgeMinecraftVersion(wantedVersion string) (string, error) {
+	metadata, err := GetMavenMetadata(NeoForgeMavenUrl, "net.neoforged", "neoforge")
+	if err != nil {
+		return "", err
+	}
+
+	loaderVersions := make(map[string][]string)
+	for _, v := range metadata.Versioning.Versions {
+		parts := strings.SplitN(v, ".", 3)
+		if len(parts) < 3 {
+			continue
+		}
+		// Snapshot builds aren't numbered after a release (e.g. "0.25w14craftmine.3-beta")
+		if major, err := strconv.Atoi(parts[0]); err != nil || major < 20 {
+			continue
+		}
+
+		minecraftVersion := "1." + parts[0]
+		if parts[1] != "0" {
+			minecraftVersion += "." + parts[1]
+		}
+		loaderVersions[minecraftVersion] = append(loaderVersions[minecraftVersion], v)
+	}
+	return resolveLatestMinecraftVersion(loaderVersions, wantedVersion)
+}
+
+// resolveLatestMinecraftVersion picks the newest Minecraft version out of loader versions grouped by Minecraft version.
+// "@latest-stable" only considers Minecraft versions that have a loader version without a pre-release suffix.
+func resolveLatestMinecraftVersion(loaderVersions map[string][]string, wantedVersion string) (string, error) {
+	var latest string
+	for minecraftVersion, versions := range loaderVersions {
+		if wantedVersion == "@latest-stable" && !slices.ContainsFunc(versions, func(v string) bool { return !strings.Contains(v, "-") }) {
+			continue
+		}
+		if latest == "" || compareVersions(minecraftVersion, latest) > 0 {
+			latest = minecraftVersion
+		}
+	}
+
+	if latest == "" {
+		return "", fmt.Errorf("no Minecraft versions found")
+	}
+	return latest, nil
+}
+
+// mavenServerInstaller resolves a Forge or NeoForge installer. Their servers are started with the run scripts
+// the installer produces, so there's no launcher jar to save as the loaderFile.
+func mavenServerInstaller(repository, groupId, artifactId, version, loaderVersion string) *ServerJar {
+	fileName := fmt.Sprintf("%s-%s-installer.jar", artifactId, version)
+	downloadUrl := MavenArtifactUrl(repository, groupId, artifactId, version, fileName)
+
+	return &ServerJar{
+		Dependency: &manifest.Dependency{
+			ProjectId:   artifactId,
+			Version:     loaderVersion,
+			FileName:    fileName,
+			FileHash:    GetMavenChecksum(downloadUrl),
+			DownloadUrl: downloadUrl,
+		},
+		InstallerArgs:  []string{"--installServer", "{installDir}"},
+		PreservedFiles: []string{"user_jvm_args.txt"},
+	}
+}
+
+// resolveLoaderVersion resolves "@latest" or a pinned version against the available loader versions.
+// "@latest" prefers the newest version without a pre-release suffix (e.g. "-beta").
+func resolveLoaderVersion(loaderVersions []string, wantedVersion string) string {
+	if wantedVersion != "@latest" {
+		for _, v := range loaderVersions {
+			if v == wantedVersion {
+				return v
+			}
+		}
+		return ""
+	}
+
+	sorted := append([]string(nil), loaderVersions...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return compareVersions(sorted[i], sorted[j]) > 0
+	})
+
+	for _, v := range sorted {
+		if !strings.Contains(v, "-") {
+			return v
+		}
+	}
+	if len(sorted) > 0 {
+		log.Warn(fmt.Sprintf("No stable loader version found, using %s", sorted[0]))
+		return sorted[0]
+	}
+	return ""
+}
+
+// neoForgeVersionPrefix maps a Minecraft version to the NeoForge version prefix ("1.21.7" -> "21.7.", "1.21" -> "21.0.")
+func neoForgeVersionPrefix(minecraftVersion string) string {
+	parts := strings.Split(strings.TrimPrefix(minecraftVersion, "1."), ".")
+	if len(parts) == 1 {
+		parts = append(parts, "0")
+	}
+	return parts[0] + "." + parts[1] + "."
+}
+
+// compareVersions compares dot separated versions numerically where possible
+func compareVersions(a, b string) int {
+	aParts := strings.FieldsFunc(a, isVersionSeparator)
+	bParts := strings.FieldsFunc(b, isVersionSeparator)
+
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		aNum, aErr := strconv.Atoi(aParts[i])
+		bNum, bErr := strconv.Atoi(bParts[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if aNum != bNum {
+				return aNum - bNum
+			}
+		case aParts[i] != bParts[i]:
+			return strings.Compare(aParts[i], bParts[i])
+		}
+	}
+	return len(aParts) - len(bParts)
+}
+
+func isVersionSeparator(r rune) bool {
+	return r == '.' || r == '-' || r == '+'
+}
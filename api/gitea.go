@@ -0,0 +1,25 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GetGiteaReleases gets the releases of a Gitea or Forgejo repository ("owner/repo"), newest first.
+// The base URL is the instance (e.g. "https://codeberg.org"), the token is only needed for private repositories.
+func GetGiteaReleases(baseUrl, repo, token string) ([]GiteaRelease, error) {
+	var releases []GiteaRelease
+	requestUrl := fmt.Sprintf("%s/api/v1/repos/%s/releases?limit=50", strings.TrimSuffix(baseUrl, "/"), repo)
+	err := getWithHeaders(requestUrl, GiteaHeaders(token), &releases)
+	return releases, err
+}
+
+// GiteaHeaders returns the headers for authenticated Gitea requests, nil without a token
+func GiteaHeaders(token string) map[string]string {
+	if token == "" {
+		return nil
+	}
+	return map[string]string{
+		"Authorization": "token " + token,
+	}
+}
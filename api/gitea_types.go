@@ -0,0 +1,20 @@
+package api
+
+import "time"
+
+type GiteaRelease struct {
+	ID          int          `json:"id"`
+	TagName     string       `json:"tag_name"`
+	Name        string       `json:"name"`
+	Draft       bool         `json:"draft"`
+	Prerelease  bool         `json:"prerelease"`
+	PublishedAt time.Time    `json:"published_at"`
+	Assets      []GiteaAsset `json:"assets"`
+}
+
+type GiteaAsset struct {
+	ID                 int    `json:"id"`
+	Name               string `json:"name"`
+	Size               int    `json:"size"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
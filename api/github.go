@@ -0,0 +1,35 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+)
+
+const GitHubApiUrl = "https://api.github.com"
+
+// GetGitHubReleases gets the releases of a GitHub repository ("owner/repo"), newest first.
+// The token is optional and only needed for private repositories or to avoid rate limits.
+func GetGitHubReleases(repo, token string) ([]GitHubRelease, error) {
+	var releases []GitHubRelease
+	err := getWithHeaders(fmt.Sprintf("%s/repos/%s/releases?per_page=100", GitHubApiUrl, repo), GitHubHeaders(token), &releases)
+	return releases, err
+}
+
+// GitHubHeaders returns the headers for authenticated GitHub API requests, nil without a token
+func GitHubHeaders(token string) map[string]string {
+	if token == "" {
+		return nil
+	}
+	return map[string]string{
+		"Authorization": "Bearer " + token,
+	}
+}
+
+// Hash returns the hex encoded hash of the asset, or an empty string if GitHub didn't compute one
+func (a *GitHubAsset) Hash() string {
+	_, hash, found := strings.Cut(a.Digest, ":")
+	if !found {
+		return ""
+	}
+	return hash
+}
@@ -0,0 +1,28 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// GetGitLabReleases gets the releases of a GitLab project ("group/project"), newest first.
+// The base URL is the GitLab instance (e.g. "https://gitlab.com"), the token is only needed for private projects.
+func GetGitLabReleases(baseUrl, project, token string) ([]GitLabRelease, error) {
+	var releases []GitLabRelease
+	requestUrl := fmt.Sprintf("%s/api/v4/projects/%s/releases?per_page=100", strings.TrimSuffix(baseUrl, "/"), url.PathEscape(project))
+	err := getWithHeaders(requestUrl, GitLabHeaders(token), &releases)
+	return releases, err
+}
+
+// GitLabHeaders returns the headers for authenticated GitLab requests, nil without a token.
+// The token is sent as a bearer token rather than "PRIVATE-TOKEN", as Go only drops the Authorization header
+// when a download redirects to another host (e.g. an asset link's CDN).
+func GitLabHeaders(token string) map[string]string {
+	if token == "" {
+		return nil
+	}
+	return map[string]string{
+		"Authorization": "Bearer " + token,
+	}
+}
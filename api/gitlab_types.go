@@ -0,0 +1,21 @@
+package api
+
+import "time"
+
+type GitLabRelease struct {
+	Name            string    `json:"name"`
+	TagName         string    `json:"tag_name"`
+	UpcomingRelease bool      `json:"upcoming_release"`
+	ReleasedAt      time.Time `json:"released_at"`
+	Assets          struct {
+		Links []GitLabReleaseLink `json:"links"`
+	} `json:"assets"`
+}
+
+type GitLabReleaseLink struct {
+	ID             int    `json:"id"`
+	Name           string `json:"name"`
+	URL            string `json:"url"`
+	DirectAssetURL string `json:"direct_asset_url"`
+	LinkType       string `json:"link_type"`
+}
@@ -3,6 +3,9 @@ package api
 import (
 	"fmt"
 	"net/url"
+	"path"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/SKevo18/server_updater/manifest"
@@ -18,107 +21,163 @@ func GetHangarProject(projectSlugOrId string) (*HangarProject, error) {
 	return &project, err
 }
 
-// GetHangarVersionsFor gets all versions of a project with compatibility filtering
-func GetHangarVersionsFor(project *HangarProject, server manifest.Server) ([]HangarVersion, error) {
+// hangarPageSize is the maximum number of versions Hangar returns per page
+const hangarPageSize = 25
+
+// GetHangarVersionsFor gets the versions of a project with compatibility filtering, newest first.
+// Pages are requested until a version satisfies found, or all pages are exhausted if found is nil.
+func GetHangarVersionsFor(project *HangarProject, server manifest.Server, found func(*HangarVersion) bool) ([]HangarVersion, error) {
 	params := url.Values{}
 
 	// Map common loaders to Hangar platform names
 	platform := mapLoaderToPlatform(server.Loader)
 	if platform != "" {
 		params.Add("platform", platform)
-		params.Add("platformVersion", server.MinecraftVersion)
+
+		// Proxy platform versions are proxy versions, not Minecraft versions
+		if platform == "PAPER" {
+			params.Add("platformVersion", server.MinecraftVersion)
+		}
 	}
 
-	// Add pagination - get first 25 versions
-	params.Add("limit", "25")
-	params.Add("offset", "0")
+	return getHangarVersions(project, params, found)
+}
 
-	requestUrl := fmt.Sprintf("%s/projects/%s/versions?%s",
-		HangarApiUrl,
-		project.Namespace.Slug,
-		params.Encode(),
-	)
+// GetAllHangarVersionsFor gets the versions of a project without compatibility filtering, newest first.
+// Pages are requested until a version satisfies found, or all pages are exhausted if found is nil.
+func GetAllHangarVersionsFor(project *HangarProject, found func(*HangarVersion) bool) ([]HangarVersion, error) {
+	return getHangarVersions(project, url.Values{}, found)
+}
 
-	log.Debug(fmt.Sprintf("Requesting Hangar versions: %s", requestUrl))
+func getHangarVersions(project *HangarProject, params url.Values, found func(*HangarVersion) bool) ([]HangarVersion, error) {
+	var versions []HangarVersion
+	for offset := 0; ; offset += hangarPageSize {
+		params.Set("limit", strconv.Itoa(hangarPageSize))
+		params.Set("offset", strconv.Itoa(offset))
+
+		requestUrl := fmt.Sprintf("%s/projects/%s/versions?%s",
+			HangarApiUrl,
+			project.Namespace.Slug,
+			params.Encode(),
+		)
+
+		log.Debug(fmt.Sprintf("Requesting Hangar versions: %s", requestUrl))
+
+		var response HangarVersionsResponse
+		err := get(requestUrl, &response)
+		if err != nil {
+			log.Debug(fmt.Sprintf("Error getting Hangar versions: %v", err))
+			return nil, err
+		}
+		versions = append(versions, response.Result...)
 
-	var response HangarVersionsResponse
-	err := get(requestUrl, &response)
-	if err != nil {
-		log.Debug(fmt.Sprintf("Error getting Hangar versions: %v", err))
-		return nil, err
+		if found != nil && slices.ContainsFunc(response.Result, func(v HangarVersion) bool { return found(&v) }) {
+			break
+		}
+		if len(response.Result) == 0 || offset+len(response.Result) >= response.Pagination.Count {
+			break
+		}
 	}
 
-	log.Debug(fmt.Sprintf("Found %d Hangar versions", len(response.Result)))
+	log.Debug(fmt.Sprintf("Found %d Hangar versions", len(versions)))
 
-	return response.Result, nil
+	return versions, nil
 }
 
-// GetAllHangarVersionsFor gets all versions of a project without compatibility filtering
-func GetAllHangarVersionsFor(project *HangarProject) ([]HangarVersion, error) {
-	params := url.Values{}
-	params.Add("limit", "25")
-	params.Add("offset", "0")
-
-	requestUrl := fmt.Sprintf("%s/projects/%s/versions?%s",
-		HangarApiUrl,
-		project.Namespace.Slug,
-		params.Encode(),
-	)
-
-	var response HangarVersionsResponse
-	err := get(requestUrl, &response)
-	if err != nil {
-		return nil, err
+// GetHangarDownload gets the download of a version for the server's platform
+func GetHangarDownload(version *HangarVersion, server manifest.Server) (*HangarDownload, error) {
+	platform := mapLoaderToPlatform(server.Loader)
+	if platform == "" {
+		return nil, fmt.Errorf("unsupported loader: %s", server.Loader)
 	}
 
-	return response.Result, nil
-}
-
-// ResolveHangarVersion resolves the wanted version string to a specific HangarVersion
-func ResolveHangarVersion(versions []HangarVersion, wantedVersion string) *HangarVersion {
-	if wantedVersion == "@latest" && len(versions) > 0 {
-		return &versions[0]
+	download, ok := version.Downloads[platform]
+	if !ok {
+		return nil, fmt.Errorf("version %s has no %s download", version.Name, platform)
 	}
+	return &download, nil
+}
 
-	for _, v := range versions {
-		if v.Name == wantedVersion {
-			return &v
-		}
+// Url returns the URL to download the file from, which is either hosted on Hangar or externally
+func (d *HangarDownload) Url() string {
+	if d.FileInfo == nil && d.ExternalURL != "" {
+		return d.ExternalURL
 	}
-	return nil
+	return d.DownloadURL
 }
 
-// GetHangarDownloadUrl gets the download URL for a specific platform
-func GetHangarDownloadUrl(project *HangarProject, version *HangarVersion, server manifest.Server) (string, string, error) {
-	platform := mapLoaderToPlatform(server.Loader)
-	if platform == "" {
-		return "", "", fmt.Errorf("unsupported loader: %s", server.Loader)
+// FileName returns the name of the downloaded file. External downloads have no file info,
+// so the name is taken from the URL, or generated from the project name and version.
+func (d *HangarDownload) FileName(project *HangarProject, version *HangarVersion) string {
+	if d.FileInfo != nil {
+		return d.FileInfo.Name
 	}
 
-	// The download URL follows Hangar's pattern
-	downloadUrl := fmt.Sprintf("%s/projects/%s/versions/%s/%s/download",
-		HangarApiUrl,
-		project.Namespace.Slug,
-		version.Name,
-		platform,
-	)
+	if externalUrl, err := url.Parse(d.ExternalURL); err == nil && strings.HasSuffix(externalUrl.Path, ".jar") {
+		return path.Base(externalUrl.Path)
+	}
 
-	// Generate filename based on project name and version
-	filename := fmt.Sprintf("%s-%s.jar", project.Name, version.Name)
 	// Clean filename of any invalid characters
-	filename = strings.ReplaceAll(filename, " ", "-")
+	return strings.ReplaceAll(fmt.Sprintf("%s-%s.jar", project.Name, version.Name), " ", "-")
+}
 
-	return downloadUrl, filename, nil
+// Hash returns the SHA-256 hash of the file, or an empty string for external downloads
+func (d *HangarDownload) Hash() string {
+	if d.FileInfo == nil {
+		return ""
+	}
+	return d.FileInfo.Sha256Hash
 }
 
-// GetHangarRequiredDependencies gets the required dependencies for a version
-func GetHangarRequiredDependencies(project *HangarProject, version *HangarVersion, server manifest.Server, downloadIncompatible bool) ([]*manifest.Dependency, error) {
+// GetHangarDependencies gets the required and optional dependencies for a version on the server's platform.
+// Dependencies that are only hosted externally can't be resolved, so required ones are reported as warnings.
+// Optional dependencies are only looked up once they are selected, so they have no project ID yet.
+func GetHangarDependencies(project *HangarProject, version *HangarVersion, server manifest.Server, downloadIncompatible bool) ([]*manifest.Dependency, error) {
 	deps := make([]*manifest.Dependency, 0)
 
-	// Currently, Hangar API doesn't provide dependency project information in a format
-	// that allows automatic resolution like Modrinth does.
-	// The platformDependencies field contains supported Minecraft versions, not plugin dependencies.
-	// Future enhancement could parse description or other fields for dependency information.
+	platform := mapLoaderToPlatform(server.Loader)
+	for _, dep := range version.PluginDependencies[platform] {
+		dependencyType := manifest.DependencyRequired
+		if !dep.Required {
+			dependencyType = manifest.DependencyOptional
+		}
+
+		if dep.ExternalURL != "" {
+			if dep.Required {
+				log.Warn(fmt.Sprintf("%s requires %s, which has to be downloaded manually from %s", project.Name, dep.Name, dep.ExternalURL))
+			} else {
+				log.Debug(fmt.Sprintf("Skipping optional dependency %s of %s, which is only hosted at %s", dep.Name, project.Name, dep.ExternalURL))
+			}
+			continue
+		}
+
+		depSlug := dep.Name
+		if dep.Namespace != nil {
+			depSlug = dep.Namespace.Slug
+		}
+
+		newDep := &manifest.Dependency{
+			WantedVersion:        "@latest",
+			DependencyType:       dependencyType,
+			DownloadIncompatible: downloadIncompatible, // Inherit from parent
+			Metadata: map[string]any{
+				"source.hangar": map[string]any{
+					"projectSlug": depSlug,
+				},
+			},
+		}
+
+		if dep.Required {
+			depProject, err := GetHangarProject(depSlug)
+			if err != nil {
+				log.Warn(fmt.Sprintf("Dependency %s of %s not found on Hangar: %s", dep.Name, project.Name, err))
+				continue
+			}
+			newDep.ProjectId = fmt.Sprintf("%d", depProject.ProjectID)
+			newDep.Metadata["source.hangar"].(map[string]any)["projectSlug"] = depProject.Namespace.Slug
+		}
+		deps = append(deps, newDep)
+	}
 
 	return deps, nil
 }
@@ -126,7 +185,7 @@ func GetHangarRequiredDependencies(project *HangarProject, version *HangarVersio
 // mapLoaderToPlatform maps common loader names to Hangar platform names
 func mapLoaderToPlatform(loader string) string {
 	switch strings.ToLower(loader) {
-	case "paper", "spigot", "bukkit":
+	case "paper", "spigot", "bukkit", "purpur", "folia", "leaf", "pufferfish":
 		return "PAPER"
 	case "velocity":
 		return "VELOCITY"
@@ -93,11 +93,24 @@ type HangarVersion struct {
 	ReviewState string    `json:"reviewState"`
 	Channel     Channel   `json:"channel"`
 	PinnedUsers []string  `json:"pinnedUsers"`
-	Downloads   struct {
-		// Platform-specific downloads (e.g., "PAPER", "VELOCITY", etc.)
-	} `json:"downloads"`
-	PlatformDependencies          map[string][]string `json:"platformDependencies"`
-	PlatformDependenciesFormatted map[string][]string `json:"platformDependenciesFormatted"`
+	// Platform-specific downloads (e.g., "PAPER", "VELOCITY", etc.)
+	Downloads                     map[string]HangarDownload           `json:"downloads"`
+	PluginDependencies            map[string][]HangarPluginDependency `json:"pluginDependencies"`
+	PlatformDependencies          map[string][]string                 `json:"platformDependencies"`
+	PlatformDependenciesFormatted map[string][]string                 `json:"platformDependenciesFormatted"`
+}
+
+type HangarDownload struct {
+	// File info of files hosted on Hangar, nil for external downloads
+	FileInfo    *HangarFileInfo `json:"fileInfo"`
+	ExternalURL string          `json:"externalUrl"`
+	DownloadURL string          `json:"downloadUrl"`
+}
+
+type HangarFileInfo struct {
+	Name       string `json:"name"`
+	SizeBytes  int64  `json:"sizeBytes"`
+	Sha256Hash string `json:"sha256Hash"`
 }
 
 type Channel struct {
@@ -108,6 +121,15 @@ type Channel struct {
 	Flags       []string  `json:"flags"`
 }
 
+type HangarPluginDependency struct {
+	Name     string `json:"name"`
+	Required bool   `json:"required"`
+	// Namespace of dependencies hosted on Hangar (older API responses only have the name)
+	Namespace   *Namespace `json:"namespace"`
+	ExternalURL string     `json:"externalUrl"`
+	Platform    string     `json:"platform"`
+}
+
 type PlatformDependency struct {
 	Name             string   `json:"name"`
 	Required         bool     `json:"required"`
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// GetJenkinsJobs gets the top-level jobs of a Jenkins instance
+func GetJenkinsJobs(jenkinsUrl string) ([]JenkinsJobLink, error) {
+	var jobs JenkinsJobs
+	err := get(fmt.Sprintf("%s/api/json?tree=%s", strings.TrimSuffix(jenkinsUrl, "/"), url.QueryEscape("jobs[name,url]")), &jobs)
+	return jobs.Jobs, err
+}
+
+// GetJenkinsJob gets a Jenkins job with its recent builds (newest first) and their artifacts
+func GetJenkinsJob(jenkinsUrl, job string) (*JenkinsJob, error) {
+	var jenkinsJob JenkinsJob
+	tree := "name,url,lastSuccessfulBuild[number,url],lastStableBuild[number,url],builds[number,result,timestamp,url,artifacts[displayPath,fileName,relativePath]]"
+	err := get(fmt.Sprintf("%s/api/json?tree=%s", jenkinsJobUrl(jenkinsUrl, job), url.QueryEscape(tree)), &jenkinsJob)
+	return &jenkinsJob, err
+}
+
+// GetJenkinsBuild gets a build of a Jenkins job. The job can be nested in folders ("folder/job"),
+// and the build can be a build number or a permalink such as "lastSuccessfulBuild"
+func GetJenkinsBuild(jenkinsUrl, job, build string) (*JenkinsBuild, error) {
+	var jenkinsBuild JenkinsBuild
+	err := get(fmt.Sprintf("%s/%s/api/json", jenkinsJobUrl(jenkinsUrl, job), build), &jenkinsBuild)
+	return &jenkinsBuild, err
+}
+
+// ArtifactUrl returns the download URL of an artifact of the build
+func (b *JenkinsBuild) ArtifactUrl(artifact *JenkinsArtifact) string {
+	return fmt.Sprintf("%s/artifact/%s", strings.TrimSuffix(b.URL, "/"), artifact.RelativePath)
+}
+
+func jenkinsJobUrl(jenkinsUrl, job string) string {
+	jobUrl := strings.TrimSuffix(jenkinsUrl, "/")
+	for _, part := range strings.Split(strings.Trim(job, "/"), "/") {
+		jobUrl += "/job/" + part
+	}
+	return jobUrl
+}
+
+// FindJenkinsArtifact finds the first artifact whose file name matches the glob pattern
+func FindJenkinsArtifact(artifacts []JenkinsArtifact, pattern string) *JenkinsArtifact {
+	for i := range artifacts {
+		if matched, _ := path.Match(pattern, artifacts[i].FileName); matched {
+			return &artifacts[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package api
+
+type JenkinsJobs struct {
+	Jobs []JenkinsJobLink `json:"jobs"`
+}
+
+type JenkinsJobLink struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type JenkinsJob struct {
+	Name                string            `json:"name"`
+	URL                 string            `json:"url"`
+	Builds              []JenkinsBuild    `json:"builds"`
+	LastSuccessfulBuild *JenkinsBuildLink `json:"lastSuccessfulBuild"`
+	LastStableBuild     *JenkinsBuildLink `json:"lastStableBuild"`
+}
+
+type JenkinsBuildLink struct {
+	Number int    `json:"number"`
+	URL    string `json:"url"`
+}
+
+type JenkinsBuild struct {
+	Number    int               `json:"number"`
+	Result    string            `json:"result"`
+	Timestamp int64             `json:"timestamp"`
+	URL       string            `json:"url"`
+	Artifacts []JenkinsArtifact `json:"artifacts"`
+}
+
+type JenkinsArtifact struct {
+	DisplayPath  string `json:"displayPath"`
+	FileName     string `json:"fileName"`
+	RelativePath string `json:"relativePath"`
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SKevo18/server_updater/manifest"
+)
+
+const LeafRepository = "Winds-Studio/Leaf"
+
+// ResolveLeafMinecraftVersion resolves "@latest" and "@latest-stable" to the Minecraft version of the newest Leaf release
+func ResolveLeafMinecraftVersion() (string, error) {
+	releases, err := GetGitHubReleases(LeafRepository, "")
+	if err != nil {
+		return "", err
+	}
+
+	for _, release := range releases {
+		if release.Draft || release.Prerelease {
+			continue
+		}
+		for _, asset := range release.Assets {
+			name, ok := strings.CutPrefix(asset.Name, "leaf-")
+			if !ok || !strings.HasSuffix(name, ".jar") {
+				continue
+			}
+			if i := strings.LastIndex(name, "-"); i > 0 {
+				return name[:i], nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no leaf releases found")
+}
+
+// GetLeafServerJar resolves the Leaf server jar from its GitHub releases.
+// Release assets are named "leaf-{minecraftVersion}-{build}.jar".
+func GetLeafServerJar(server manifest.Server) (*ServerJar, error) {
+	releases, err := GetGitHubReleases(LeafRepository, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get leaf releases: %w", err)
+	}
+
+	prefix := fmt.Sprintf("leaf-%s-", server.MinecraftVersion)
+	for _, release := range releases {
+		if release.Draft {
+			continue
+		}
+
+		for i := range release.Assets {
+			asset := &release.Assets[i]
+			build, ok := strings.CutPrefix(asset.Name, prefix)
+			if !ok {
+				continue
+			}
+			build, ok = strings.CutSuffix(build, ".jar")
+			if !ok || (server.LoaderVersion != "@latest" && build != server.LoaderVersion) {
+				continue
+			}
+
+			return &ServerJar{
+				Dependency: &manifest.Dependency{
+					ProjectId:   "leaf",
+					Version:     build,
+					FileName:    asset.Name,
+					FileHash:    asset.Hash(),
+					DownloadUrl: asset.BrowserDownloadURL,
+					SaveAs:      server.CanonicalLoaderFile(build),
+				},
+			}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("leaf build '%s' not found for Minecraft %s", server.LoaderVersion, server.MinecraftVersion)
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GetMavenMetadata gets the maven-metadata.xml of an artifact from a Maven repository
+func GetMavenMetadata(repository, groupId, artifactId string) (*MavenMetadata, error) {
+	var metadata MavenMetadata
+	err := getXML(fmt.Sprintf("%s/maven-metadata.xml", mavenArtifactDir(repository, groupId, artifactId)), &metadata)
+	return &metadata, err
+}
+
+// GetMavenSnapshotMetadata gets the maven-metadata.xml of a SNAPSHOT version, which lists its timestamped builds
+func GetMavenSnapshotMetadata(repository, groupId, artifactId, version string) (*MavenMetadata, error) {
+	var metadata MavenMetadata
+	err := getXML(fmt.Sprintf("%s/%s/maven-metadata.xml", mavenArtifactDir(repository, groupId, artifactId), version), &metadata)
+	return &metadata, err
+}
+
+// SnapshotValue returns the timestamped version of the latest build of a SNAPSHOT version (e.g. "1.0-20250101.120000-3")
+func (m *MavenMetadata) SnapshotValue(classifier, extension string) string {
+	for _, v := range m.Versioning.SnapshotVersions {
+		if v.Classifier == classifier && v.Extension == extension {
+			return v.Value
+		}
+	}
+
+	// Older repositories only record the latest timestamp and build number
+	snapshot := m.Versioning.Snapshot
+	if snapshot.Timestamp == "" {
+		return m.Version
+	}
+	return strings.Replace(m.Version, "SNAPSHOT", fmt.Sprintf("%s-%d", snapshot.Timestamp, snapshot.BuildNumber), 1)
+}
+
+// MavenArtifactUrl returns the URL of a file of a specific artifact version
+func MavenArtifactUrl(repository, groupId, artifactId, version, fileName string) string {
+	return fmt.Sprintf("%s/%s/%s", mavenArtifactDir(repository, groupId, artifactId), version, fileName)
+}
+
+// GetMavenChecksum gets the checksum published next to an artifact (".sha256" or ".sha1" sidecar file).
+// Returns an empty string if the repository doesn't publish any.
+func GetMavenChecksum(artifactUrl string) string {
+	for _, extension := range []string{".sha256", ".sha1"} {
+		checksum, err := getText(artifactUrl + extension)
+		if err != nil {
+			continue
+		}
+		// Some repositories append the file name after the hash
+		if fields := strings.Fields(checksum); len(fields) > 0 {
+			return fields[0]
+		}
+	}
+	return ""
+}
+
+func mavenArtifactDir(repository, groupId, artifactId string) string {
+	return fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(repository, "/"), strings.ReplaceAll(groupId, ".", "/"), artifactId)
+}
@@ -2,11 +2,13 @@ package api
 
 import (
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"os"
+	"strings"
 
 	"github.com/SKevo18/server_updater/manifest"
 	log "github.com/gwillem/go-simplelog"
@@ -14,6 +16,8 @@ import (
 
 var CanonicalModrinthApiUrl = getModrinthApiUrl()
 
+const userAgent = "SKevo18/server_updater (https://github.com/SKevo18/server_updater)"
+
 const (
 	apiUrlRoot        = "https://api.modrinth.com/v2"
 	stagingApiUrlRoot = "https://staging-api.modrinth.com/v2"
@@ -39,7 +43,11 @@ func GetVersionsFor(project *ModrinthProject, server manifest.Server) ([]Modrint
 
 	// Properly encode query parameters
 	params := url.Values{}
-	params.Add("loaders", fmt.Sprintf("[\"%s\"]", server.Loader))
+	loaders, err := json.Marshal(modrinthLoaders(server.Loader))
+	if err != nil {
+		return nil, err
+	}
+	params.Add("loaders", string(loaders))
 	params.Add("game_versions", fmt.Sprintf("[\"%s\"]", server.MinecraftVersion))
 
 	requestUrl := fmt.Sprintf("%s/project/%s/version?%s",
@@ -48,7 +56,7 @@ func GetVersionsFor(project *ModrinthProject, server manifest.Server) ([]Modrint
 		params.Encode(),
 	)
 
-	err := get(requestUrl, &versions)
+	err = get(requestUrl, &versions)
 	if err != nil {
 		return nil, err
 	}
@@ -56,6 +64,19 @@ func GetVersionsFor(project *ModrinthProject, server manifest.Server) ([]Modrint
 	return versions, nil
 }
 
+// modrinthLoaders maps the server loader to the Modrinth loaders whose plugins it can run.
+// Paper forks run Paper plugins, but Modrinth only knows some of them as separate loaders.
+func modrinthLoaders(loader string) []string {
+	switch strings.ToLower(loader) {
+	case "purpur", "folia":
+		return []string{strings.ToLower(loader), "paper"}
+	case "leaf", "pufferfish":
+		return []string{"paper"}
+	default:
+		return []string{loader}
+	}
+}
+
 // GetAllVersionsFor gets all versions of a project without compatibility filtering
 func GetAllVersionsFor(project *ModrinthProject) ([]ModrinthVersion, error) {
 	var versions []ModrinthVersion
@@ -80,94 +101,59 @@ func GetVersion(versionId string) (*ModrinthVersion, error) {
 	return &version, err
 }
 
-// ResolveVersion resolves the wanted version string to a specific ModrinthVersion
-func ResolveVersion(versions []ModrinthVersion, wantedVersion string) *ModrinthVersion {
-	if wantedVersion == "@latest" {
-		return &versions[0]
-	}
-
-	for _, v := range versions {
-		if v.VersionNumber == wantedVersion {
-			return &v
-		}
-	}
-	return nil
-}
-
-// GetRequiredDependencies gets the required dependencies for a version
-func GetRequiredDependencies(version *ModrinthVersion, server manifest.Server, downloadIncompatible bool) ([]*manifest.Dependency, error) {
+// GetDependencies gets the required, optional and embedded dependencies for a version.
+// The dependency versions are resolved later, like any other dependency's "@latest", unless a version ID is pinned.
+func GetDependencies(version *ModrinthVersion, downloadIncompatible bool) ([]*manifest.Dependency, error) {
 	deps := make([]*manifest.Dependency, 0)
 	for _, dep := range version.Dependencies {
-		if dep.DependencyType == "required" {
-			if dep.ProjectID == nil {
-				log.Warn("Skipping dependency with no project ID")
-				continue
-			}
-
-			depProject, err := GetProject(*dep.ProjectID)
-			if err != nil {
-				return nil, err
-			}
-
-			var depVersions []ModrinthVersion
-			if downloadIncompatible {
-				depVersions, err = GetAllVersionsFor(depProject)
-			} else {
-				depVersions, err = GetVersionsFor(depProject, server)
-			}
+		switch dep.DependencyType {
+		case manifest.DependencyRequired, manifest.DependencyOptional, manifest.DependencyEmbedded:
+		default:
+			continue
+		}
 
+		// Dependencies on an exact version may leave out the project
+		projectId := ""
+		if dep.ProjectID != nil {
+			projectId = *dep.ProjectID
+		} else if dep.VersionID != nil {
+			depVersion, err := GetVersion(*dep.VersionID)
 			if err != nil {
-				return nil, err
-			}
-			if len(depVersions) == 0 {
-				log.Warn(fmt.Sprintf("No versions found for dependency %s", depProject.Title))
-				continue
-			}
-
-			// We just take the latest compatible version for the dependency
-			depVersion := ResolveVersion(depVersions, "@latest")
-
-			var primaryFile *ModrinthFile
-			for _, f := range depVersion.Files {
-				if f.Primary {
-					primaryFile = &f
-					break
-				}
-			}
-
-			if primaryFile == nil {
-				log.Warn(fmt.Sprintf("No primary file found for dependency %s", depProject.Title))
-				continue
+				return nil, fmt.Errorf("failed to get version %s that %s depends on: %w", *dep.VersionID, version.Name, err)
 			}
+			projectId = depVersion.ProjectID
+		} else {
+			log.Warn("Skipping dependency with no project ID")
+			continue
+		}
 
-			newDep := &manifest.Dependency{
-				ProjectId:            depProject.ID,
-				Version:              depVersion.VersionNumber,
-				WantedVersion:        depVersion.VersionNumber,
-				FileName:             primaryFile.Filename,
-				FileHash:             primaryFile.Hashes["sha512"],
-				DownloadUrl:          primaryFile.URL,
-				SaveAs:               primaryFile.Filename, // Dependencies are saved as their original filename
-				DownloadIncompatible: downloadIncompatible, // Inherit from parent
-				Metadata: map[string]any{
-					"source.modrinth": map[string]any{
-						"projectId": depProject.ID,
-					},
+		newDep := &manifest.Dependency{
+			ProjectId:            projectId,
+			WantedVersion:        "@latest",
+			DependencyType:       dep.DependencyType,
+			DownloadIncompatible: downloadIncompatible, // Inherit from parent
+			Metadata: map[string]any{
+				"source.modrinth": map[string]any{
+					"projectId": projectId,
 				},
-			}
-			newDep.Dependencies, err = GetRequiredDependencies(depVersion, server, downloadIncompatible)
-			if err != nil {
-				return nil, err
-			}
-			deps = append(deps, newDep)
+			},
+		}
+		if dep.VersionID != nil {
+			newDep.VersionId = *dep.VersionID
 		}
+		deps = append(deps, newDep)
 	}
 	return deps, nil
 }
 
 // DownloadFile downloads a file from a URL to a specific path
 func DownloadFile(url, path string) error {
-	resp, err := http.Get(url)
+	return DownloadFileWithHeaders(url, path, nil)
+}
+
+// DownloadFileWithHeaders downloads a file from a URL to a specific path, sending additional headers (e.g. authorization)
+func DownloadFileWithHeaders(url, path string, headers map[string]string) error {
+	resp, err := request(url, headers)
 	if err != nil {
 		return err
 	}
@@ -184,7 +170,11 @@ func DownloadFile(url, path string) error {
 }
 
 func get(url string, target any) error {
-	resp, err := http.Get(url)
+	return getWithHeaders(url, nil, target)
+}
+
+func getWithHeaders(url string, headers map[string]string, target any) error {
+	resp, err := request(url, headers)
 	if err != nil {
 		return err
 	}
@@ -192,3 +182,47 @@ func get(url string, target any) error {
 
 	return json.NewDecoder(resp.Body).Decode(target)
 }
+
+func getXML(url string, target any) error {
+	resp, err := request(url, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return xml.NewDecoder(resp.Body).Decode(target)
+}
+
+func getText(url string) (string, error) {
+	resp, err := request(url, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	return string(body), err
+}
+
+// request performs a GET request, failing on non-2xx responses.
+// Some APIs (e.g. PaperMC's) reject requests without a User-Agent.
+func request(url string, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status %s for %s", resp.Status, url)
+	}
+	return resp, nil
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/SKevo18/server_updater/manifest"
+)
+
+const MojangVersionManifestUrl = "https://piston-meta.mojang.com/mc/game/version_manifest_v2.json"
+
+// GetMojangVersionManifest gets the list of all Minecraft versions from Mojang
+func GetMojangVersionManifest() (*MojangVersionManifest, error) {
+	var versionManifest MojangVersionManifest
+	err := get(MojangVersionManifestUrl, &versionManifest)
+	return &versionManifest, err
+}
+
+// GetMojangVersion gets the details of a Minecraft version
+func GetMojangVersion(entry *MojangVersionEntry) (*MojangVersion, error) {
+	var version MojangVersion
+	err := get(entry.URL, &version)
+	return &version, err
+}
+
+// ResolveMojangVersion resolves the wanted Minecraft version, which can be
+// "@latest-release" (or "@latest" and "@latest-stable") and "@latest-snapshot"
+func ResolveMojangVersion(versionManifest *MojangVersionManifest, wantedVersion string) *MojangVersionEntry {
+	switch wantedVersion {
+	case "@latest", "@latest-stable", "@latest-release":
+		wantedVersion = versionManifest.Latest.Release
+	case "@latest-snapshot":
+		wantedVersion = versionManifest.Latest.Snapshot
+	}
+
+	for i := range versionManifest.Versions {
+		if versionManifest.Versions[i].ID == wantedVersion {
+			return &versionManifest.Versions[i]
+		}
+	}
+	return nil
+}
+
+// ResolveVanillaMinecraftVersion resolves the "@latest..." aliases of ResolveMojangVersion to a Minecraft version
+func ResolveVanillaMinecraftVersion(wantedVersion string) (string, error) {
+	versionManifest, err := GetMojangVersionManifest()
+	if err != nil {
+		return "", err
+	}
+
+	entry := ResolveMojangVersion(versionManifest, wantedVersion)
+	if entry == nil {
+		return "", fmt.Errorf("Minecraft version '%s' not found", wantedVersion)
+	}
+	return entry.ID, nil
+}
+
+// GetVanillaServerJar resolves the vanilla server jar. The loader version is the resolved Minecraft version.
+func GetVanillaServerJar(server manifest.Server) (*ServerJar, error) {
+	versionManifest, err := GetMojangVersionManifest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Minecraft versions: %w", err)
+	}
+
+	entry := ResolveMojangVersion(versionManifest, server.MinecraftVersion)
+	if entry == nil {
+		return nil, fmt.Errorf("Minecraft version '%s' not found", server.MinecraftVersion)
+	}
+
+	version, err := GetMojangVersion(entry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Minecraft version %s: %w", entry.ID, err)
+	}
+
+	download, ok := version.Downloads["server"]
+	if !ok {
+		return nil, fmt.Errorf("Minecraft %s has no server jar", version.ID)
+	}
+
+	return &ServerJar{
+		Dependency: &manifest.Dependency{
+			ProjectId:   "vanilla",
+			Version:     version.ID,
+			FileName:    fmt.Sprintf("minecraft_server.%s.jar", version.ID),
+			FileHash:    download.SHA1,
+			DownloadUrl: download.URL,
+			SaveAs:      server.CanonicalLoaderFile(version.ID),
+		},
+	}, nil
+}
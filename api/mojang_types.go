@@ -0,0 +1,32 @@
+package api
+
+import "time"
+
+type MojangVersionManifest struct {
+	Latest struct {
+		Release  string `json:"release"`
+		Snapshot string `json:"snapshot"`
+	} `json:"latest"`
+	Versions []MojangVersionEntry `json:"versions"`
+}
+
+type MojangVersionEntry struct {
+	ID          string    `json:"id"`
+	Type        string    `json:"type"`
+	URL         string    `json:"url"`
+	Time        time.Time `json:"time"`
+	ReleaseTime time.Time `json:"releaseTime"`
+	SHA1        string    `json:"sha1"`
+}
+
+type MojangVersion struct {
+	ID        string                    `json:"id"`
+	Type      string                    `json:"type"`
+	Downloads map[string]MojangDownload `json:"downloads"`
+}
+
+type MojangDownload struct {
+	SHA1 string `json:"sha1"`
+	Size int    `json:"size"`
+	URL  string `json:"url"`
+}
@@ -0,0 +1,145 @@
+package api
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/SKevo18/server_updater/manifest"
+	log "github.com/gwillem/go-simplelog"
+)
+
+const PaperMCApiUrl = "https://fill.papermc.io/v3"
+
+// GetPaperMCVersions gets all versions of a PaperMC project, newest first
+func GetPaperMCVersions(project string) ([]PaperMCVersion, error) {
+	var response PaperMCVersionsResponse
+	err := get(fmt.Sprintf("%s/projects/%s/versions", PaperMCApiUrl, project), &response)
+	return response.Versions, err
+}
+
+// ResolvePaperMCVersion resolves "@latest" to the newest version of a PaperMC project,
+// and "@latest-stable" to the newest version that has a stable build
+func ResolvePaperMCVersion(project, wantedVersion string) (string, error) {
+	versions, err := GetPaperMCVersions(project)
+	if err != nil {
+		return "", err
+	}
+
+	for _, v := range versions {
+		if wantedVersion != "@latest-stable" {
+			return v.Version.ID, nil
+		}
+
+		builds, err := GetPaperMCBuilds(project, v.Version.ID)
+		if err != nil {
+			return "", err
+		}
+		if slices.ContainsFunc(builds, func(b PaperMCBuild) bool { return isStablePaperMCChannel(b.Channel) }) {
+			return v.Version.ID, nil
+		}
+	}
+	return "", fmt.Errorf("no %s versions found", project)
+}
+
+// GetPaperMCBuilds gets all builds of a PaperMC project for a Minecraft version, newest first
+func GetPaperMCBuilds(project, minecraftVersion string) ([]PaperMCBuild, error) {
+	var builds []PaperMCBuild
+	err := get(fmt.Sprintf("%s/projects/%s/versions/%s/builds", PaperMCApiUrl, project, minecraftVersion), &builds)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(builds, func(i, j int) bool {
+		return builds[i].ID > builds[j].ID
+	})
+	return builds, nil
+}
+
+// ResolvePaperMCBuild resolves the wanted build ("@latest" or a build number) to a specific PaperMCBuild.
+// "@latest" prefers the newest stable build and falls back to the newest build of any channel.
+func ResolvePaperMCBuild(builds []PaperMCBuild, wantedBuild string) *PaperMCBuild {
+	if wantedBuild == "@latest" {
+		for i := range builds {
+			if isStablePaperMCChannel(builds[i].Channel) {
+				return &builds[i]
+			}
+		}
+		if len(builds) > 0 {
+			log.Warn(fmt.Sprintf("No stable build found, using %s build %d", builds[0].Channel, builds[0].ID))
+			return &builds[0]
+		}
+		return nil
+	}
+
+	for i := range builds {
+		if strconv.Itoa(builds[i].ID) == wantedBuild {
+			return &builds[i]
+		}
+	}
+	return nil
+}
+
+// GetPaperMCServerJar resolves the server jar of a PaperMC server project (paper, folia) for the manifest's Minecraft version
+func GetPaperMCServerJar(project string, server manifest.Server) (*ServerJar, error) {
+	return getPaperMCJar(project, server.MinecraftVersion, server)
+}
+
+// GetPaperMCProxyJar resolves the jar of a PaperMC proxy project (velocity, waterfall). Proxy versions
+// (e.g. "3.4.0-SNAPSHOT" for Velocity) aren't Minecraft versions, so they are taken from the manifest's proxyVersion.
+func GetPaperMCProxyJar(project string, server manifest.Server) (*ServerJar, error) {
+	proxyVersion := server.ProxyVersion
+	if proxyVersion == "" || strings.HasPrefix(proxyVersion, "@latest") {
+		wantedVersion := proxyVersion
+		if wantedVersion == "" {
+			wantedVersion = "@latest"
+		}
+
+		var err error
+		proxyVersion, err = ResolvePaperMCVersion(project, wantedVersion)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return getPaperMCJar(project, proxyVersion, server)
+}
+
+func getPaperMCJar(project, version string, server manifest.Server) (*ServerJar, error) {
+	builds, err := GetPaperMCBuilds(project, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s builds for %s: %w", project, version, err)
+	}
+
+	build := ResolvePaperMCBuild(builds, server.LoaderVersion)
+	if build == nil {
+		return nil, fmt.Errorf("%s build '%s' not found for version %s", project, server.LoaderVersion, version)
+	}
+
+	download, ok := build.Downloads["server:default"]
+	if !ok {
+		return nil, fmt.Errorf("no server download found for %s build %d", project, build.ID)
+	}
+
+	buildNumber := strconv.Itoa(build.ID)
+	return &ServerJar{
+		Dependency: &manifest.Dependency{
+			ProjectId:   project,
+			Version:     buildNumber,
+			FileName:    download.Name,
+			FileHash:    download.Checksums["sha256"],
+			DownloadUrl: download.URL,
+			SaveAs:      server.CanonicalLoaderFile(buildNumber),
+		},
+	}, nil
+}
+
+func isStablePaperMCChannel(channel string) bool {
+	switch strings.ToUpper(channel) {
+	case "STABLE", "RECOMMENDED":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,31 @@
+package api
+
+import "time"
+
+type PaperMCVersionsResponse struct {
+	Versions []PaperMCVersion `json:"versions"`
+}
+
+type PaperMCVersion struct {
+	Version struct {
+		ID      string `json:"id"`
+		Support struct {
+			Status string `json:"status"`
+		} `json:"support"`
+	} `json:"version"`
+	Builds []int `json:"builds"`
+}
+
+type PaperMCBuild struct {
+	ID        int                        `json:"id"`
+	Time      time.Time                  `json:"time"`
+	Channel   string                     `json:"channel"`
+	Downloads map[string]PaperMCDownload `json:"downloads"`
+}
+
+type PaperMCDownload struct {
+	Name      string            `json:"name"`
+	Checksums map[string]string `json:"checksums"`
+	Size      int               `json:"size"`
+	URL       string            `json:"url"`
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/SKevo18/server_updater/manifest"
+)
+
+const PufferfishJenkinsUrl = "https://ci.pufferfish.host"
+
+// ResolvePufferfishMinecraftVersion resolves "@latest" and "@latest-stable" to the Minecraft version
+// of the last successful build of the newest Pufferfish job
+func ResolvePufferfishMinecraftVersion() (string, error) {
+	jobs, err := GetJenkinsJobs(PufferfishJenkinsUrl)
+	if err != nil {
+		return "", err
+	}
+
+	var latestJob, latestMajor string
+	for _, job := range jobs {
+		major, ok := strings.CutPrefix(job.Name, "Pufferfish-")
+		if !ok || strings.Trim(major, "0123456789.") != "" {
+			continue // e.g. "Pufferfish-Purpur-1.21"
+		}
+		if latestMajor == "" || compareVersions(major, latestMajor) > 0 {
+			latestJob, latestMajor = job.Name, major
+		}
+	}
+	if latestJob == "" {
+		return "", fmt.Errorf("no pufferfish jobs found")
+	}
+
+	jenkinsBuild, err := GetJenkinsBuild(PufferfishJenkinsUrl, latestJob, "lastSuccessfulBuild")
+	if err != nil {
+		return "", fmt.Errorf("no successful pufferfish build found in %s: %w", latestJob, err)
+	}
+
+	// Server jars are named after the Minecraft version, e.g. "pufferfish-paperclip-1.21.3-R0.1-SNAPSHOT-mojmap.jar"
+	artifact := FindJenkinsArtifact(jenkinsBuild.Artifacts, "pufferfish-paperclip-*.jar")
+	if artifact == nil {
+		return "", fmt.Errorf("pufferfish build %d of %s has no server jar", jenkinsBuild.Number, latestJob)
+	}
+	minecraftVersion, _, _ := strings.Cut(strings.TrimPrefix(artifact.FileName, "pufferfish-paperclip-"), "-")
+	return minecraftVersion, nil
+}
+
+// GetPufferfishServerJar resolves the Pufferfish server jar from its Jenkins.
+// Pufferfish has one job per major Minecraft version (e.g. "Pufferfish-1.21").
+func GetPufferfishServerJar(server manifest.Server) (*ServerJar, error) {
+	job := "Pufferfish-" + majorMinecraftVersion(server.MinecraftVersion)
+
+	build := server.LoaderVersion
+	if build == "@latest" {
+		build = "lastSuccessfulBuild"
+	}
+
+	jenkinsBuild, err := GetJenkinsBuild(PufferfishJenkinsUrl, job, build)
+	if err != nil {
+		return nil, fmt.Errorf("pufferfish build '%s' not found in %s: %w", server.LoaderVersion, job, err)
+	}
+
+	artifact := FindJenkinsArtifact(jenkinsBuild.Artifacts, fmt.Sprintf("pufferfish-paperclip-%s-*.jar", server.MinecraftVersion))
+	if artifact == nil {
+		return nil, fmt.Errorf("pufferfish build %d of %s has no server jar for Minecraft %s", jenkinsBuild.Number, job, server.MinecraftVersion)
+	}
+
+	buildNumber := strconv.Itoa(jenkinsBuild.Number)
+	return &ServerJar{
+		Dependency: &manifest.Dependency{
+			ProjectId:   "pufferfish",
+			Version:     buildNumber,
+			FileName:    artifact.FileName,
+			DownloadUrl: jenkinsBuild.ArtifactUrl(artifact),
+			SaveAs:      server.CanonicalLoaderFile(buildNumber),
+		},
+	}, nil
+}
+
+// majorMinecraftVersion returns the major part of a Minecraft version ("1.21.7" -> "1.21")
+func majorMinecraftVersion(minecraftVersion string) string {
+	parts := strings.SplitN(minecraftVersion, ".", 3)
+	if len(parts) < 2 {
+		return minecraftVersion
+	}
+	return parts[0] + "." + parts[1]
+}
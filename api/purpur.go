@@ -0,0 +1,77 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/SKevo18/server_updater/manifest"
+)
+
+const PurpurApiUrl = "https://api.purpurmc.org/v2/purpur"
+
+// GetPurpurProject gets the list of Minecraft versions supported by Purpur, oldest first
+func GetPurpurProject() (*PurpurProject, error) {
+	var project PurpurProject
+	err := get(PurpurApiUrl, &project)
+	return &project, err
+}
+
+// GetPurpurVersion gets the list of builds for a Minecraft version from the Purpur API
+func GetPurpurVersion(minecraftVersion string) (*PurpurVersion, error) {
+	var version PurpurVersion
+	err := get(fmt.Sprintf("%s/%s", PurpurApiUrl, minecraftVersion), &version)
+	return &version, err
+}
+
+// GetPurpurBuild gets a specific build from the Purpur API
+func GetPurpurBuild(minecraftVersion, build string) (*PurpurBuild, error) {
+	var purpurBuild PurpurBuild
+	err := get(fmt.Sprintf("%s/%s/%s", PurpurApiUrl, minecraftVersion, build), &purpurBuild)
+	return &purpurBuild, err
+}
+
+// ResolvePurpurMinecraftVersion resolves "@latest" and "@latest-stable" to the newest Minecraft version supported by Purpur
+func ResolvePurpurMinecraftVersion() (string, error) {
+	project, err := GetPurpurProject()
+	if err != nil {
+		return "", err
+	}
+	if len(project.Versions) == 0 {
+		return "", fmt.Errorf("no purpur versions found")
+	}
+	return project.Versions[len(project.Versions)-1], nil
+}
+
+// GetPurpurServerJar resolves the Purpur server jar
+func GetPurpurServerJar(server manifest.Server) (*ServerJar, error) {
+	version, err := GetPurpurVersion(server.MinecraftVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get purpur builds for %s: %w", server.MinecraftVersion, err)
+	}
+
+	buildNumber := server.LoaderVersion
+	if buildNumber == "@latest" {
+		buildNumber = version.Builds.Latest
+	}
+	if buildNumber == "" {
+		return nil, fmt.Errorf("no purpur builds found for Minecraft %s", server.MinecraftVersion)
+	}
+
+	build, err := GetPurpurBuild(server.MinecraftVersion, buildNumber)
+	if err != nil {
+		return nil, fmt.Errorf("purpur build '%s' not found for Minecraft %s: %w", buildNumber, server.MinecraftVersion, err)
+	}
+	if build.Result != "SUCCESS" {
+		return nil, fmt.Errorf("purpur build %s for Minecraft %s did not succeed (%s)", build.Build, server.MinecraftVersion, build.Result)
+	}
+
+	return &ServerJar{
+		Dependency: &manifest.Dependency{
+			ProjectId:   "purpur",
+			Version:     build.Build,
+			FileName:    fmt.Sprintf("purpur-%s-%s.jar", server.MinecraftVersion, build.Build),
+			FileHash:    build.MD5,
+			DownloadUrl: fmt.Sprintf("%s/%s/%s/download", PurpurApiUrl, server.MinecraftVersion, build.Build),
+			SaveAs:      server.CanonicalLoaderFile(build.Build),
+		},
+	}, nil
+}
@@ -0,0 +1,24 @@
+package api
+
+type PurpurProject struct {
+	Project  string   `json:"project"`
+	Versions []string `json:"versions"`
+}
+
+type PurpurVersion struct {
+	Project string `json:"project"`
+	Version string `json:"version"`
+	Builds  struct {
+		Latest string   `json:"latest"`
+		All    []string `json:"all"`
+	} `json:"builds"`
+}
+
+type PurpurBuild struct {
+	Project   string `json:"project"`
+	Version   string `json:"version"`
+	Build     string `json:"build"`
+	Result    string `json:"result"`
+	Timestamp int64  `json:"timestamp"`
+	MD5       string `json:"md5"`
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SKevo18/server_updater/manifest"
+)
+
+const (
+	QuiltMetaUrl  = "https://meta.quiltmc.org/v3"
+	QuiltMavenUrl = "https://maven.quiltmc.org/repository/release"
+)
+
+// GetQuiltGameVersions gets all Minecraft versions supported by Quilt, newest first
+func GetQuiltGameVersions() ([]QuiltGameVersion, error) {
+	var versions []QuiltGameVersion
+	err := get(fmt.Sprintf("%s/versions/game", QuiltMetaUrl), &versions)
+	return versions, err
+}
+
+// GetQuiltLoaderVersions gets all Quilt loader versions available for a Minecraft version, newest first
+func GetQuiltLoaderVersions(minecraftVersion string) ([]QuiltLoaderVersion, error) {
+	var versions []QuiltLoaderVersion
+	err := get(fmt.Sprintf("%s/versions/loader/%s", QuiltMetaUrl, minecraftVersion), &versions)
+	return versions, err
+}
+
+// GetQuiltInstallerVersions gets all Quilt installer versions, newest first
+func GetQuiltInstallerVersions() ([]QuiltInstallerVersion, error) {
+	var versions []QuiltInstallerVersion
+	err := get(fmt.Sprintf("%s/versions/installer", QuiltMetaUrl), &versions)
+	return versions, err
+}
+
+// ResolveQuiltMinecraftVersion resolves "@latest" to the newest Minecraft version supported by Quilt,
+// and "@latest-stable" to the newest release
+func ResolveQuiltMinecraftVersion(wantedVersion string) (string, error) {
+	versions, err := GetQuiltGameVersions()
+	if err != nil {
+		return "", err
+	}
+
+	for _, v := range versions {
+		if v.Stable || wantedVersion != "@latest-stable" {
+			return v.Version, nil
+		}
+	}
+	return "", fmt.Errorf("no quilt game versions found")
+}
+
+// GetQuiltServerJar resolves the Quilt installer. Quilt doesn't publish a standalone server launcher,
+// so the installer is run to produce the launcher, its libraries and the vanilla server jar.
+func GetQuiltServerJar(server manifest.Server) (*ServerJar, error) {
+	loaderVersions, err := GetQuiltLoaderVersions(server.MinecraftVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quilt loader versions for %s: %w", server.MinecraftVersion, err)
+	}
+
+	var loaderVersion string
+	for _, v := range loaderVersions {
+		// Quilt meta has no stability flag, pre-releases are marked by their version suffix (e.g. "-beta.1")
+		if (server.LoaderVersion == "@latest" && !strings.Contains(v.Loader.Version, "-")) || v.Loader.Version == server.LoaderVersion {
+			loaderVersion = v.Loader.Version
+			break
+		}
+	}
+	if loaderVersion == "" {
+		return nil, fmt.Errorf("quilt loader '%s' not found for Minecraft %s", server.LoaderVersion, server.MinecraftVersion)
+	}
+
+	installerVersions, err := GetQuiltInstallerVersions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quilt installer versions: %w", err)
+	}
+	if len(installerVersions) == 0 {
+		return nil, fmt.Errorf("no quilt installer found")
+	}
+	installerVersion := installerVersions[0].Version
+
+	installerFile := fmt.Sprintf("quilt-installer-%s.jar", installerVersion)
+	return &ServerJar{
+		Dependency: &manifest.Dependency{
+			ProjectId:   "quilt",
+			Version:     loaderVersion,
+			FileName:    installerFile,
+			DownloadUrl: fmt.Sprintf("%s/org/quiltmc/quilt-installer/%s/%s", QuiltMavenUrl, installerVersion, installerFile),
+			SaveAs:      server.CanonicalLoaderFile(loaderVersion),
+		},
+		InstallerArgs: []string{
+			"install", "server", server.MinecraftVersion, loaderVersion,
+			"--download-server", "--install-dir={installDir}",
+		},
+		LauncherFile: "quilt-server-launch.jar",
+	}, nil
+}
@@ -0,0 +1,21 @@
+package api
+
+type QuiltGameVersion struct {
+	Version string `json:"version"`
+	Stable  bool   `json:"stable"`
+}
+
+type QuiltLoaderVersion struct {
+	Loader struct {
+		Separator string `json:"separator"`
+		Build     int    `json:"build"`
+		Maven     string `json:"maven"`
+		Version   string `json:"version"`
+	} `json:"loader"`
+}
+
+type QuiltInstallerVersion struct {
+	URL     string `json:"url"`
+	Maven   string `json:"maven"`
+	Version string `json:"version"`
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SKevo18/server_updater/manifest"
+)
+
+// ServerJar is a resolved server jar. Installer based loaders (e.g. Quilt, Forge) resolve to an installer
+// that has to be run to produce the server, instead of the server jar itself.
+type ServerJar struct {
+	*manifest.Dependency
+
+	// Arguments to run the installer with, nil if the jar is the server itself.
+	// "{installDir}" is replaced with the directory to install into.
+	InstallerArgs []string
+
+	// Launcher jar produced by the installer, which is saved as the manifest's loaderFile
+	LauncherFile string
+
+	// Files produced by the installer that are only placed on the first install, as users are expected to edit them
+	PreservedFiles []string
+}
+
+// IsInstaller reports whether the jar is an installer that has to be run
+func (j *ServerJar) IsInstaller() bool {
+	return j.InstallerArgs != nil
+}
+
+// ResolveMinecraftVersion resolves a "@latest" or "@latest-stable" Minecraft version
+// to the newest version supported by the loader defined in the manifest
+func ResolveMinecraftVersion(server manifest.Server) (string, error) {
+	switch loader := strings.ToLower(server.Loader); loader {
+	case "vanilla", "velocity", "waterfall", "bungeecord":
+		// Proxies support any recent version, so the newest release is used for plugin lookups
+		return ResolveVanillaMinecraftVersion(server.MinecraftVersion)
+	case "paper", "folia":
+		return ResolvePaperMCVersion(loader, server.MinecraftVersion)
+	case "purpur":
+		return ResolvePurpurMinecraftVersion()
+	case "pufferfish":
+		return ResolvePufferfishMinecraftVersion()
+	case "leaf":
+		return ResolveLeafMinecraftVersion()
+	case "fabric":
+		return ResolveFabricMinecraftVersion(server.MinecraftVersion)
+	case "quilt":
+		return ResolveQuiltMinecraftVersion(server.MinecraftVersion)
+	case "forge":
+		return ResolveForgeMinecraftVersion(server.MinecraftVersion)
+	case "neoforge":
+		return ResolveNeoForgeMinecraftVersion(server.MinecraftVersion)
+	default:
+		return "", fmt.Errorf("resolving Minecraft version '%s' is not supported for loader: %s", server.MinecraftVersion, server.Loader)
+	}
+}
+
+// HasLauncher reports whether the loader produces a server jar that is saved as the manifest's loaderFile.
+// Forge and NeoForge installers produce run scripts (run.sh and run.bat) instead.
+func HasLauncher(loader string) bool {
+	switch strings.ToLower(loader) {
+	case "forge", "neoforge":
+		return false
+	default:
+		return true
+	}
+}
+
+// GetServerJar resolves the server jar for the loader defined in the manifest
+func GetServerJar(server manifest.Server) (*ServerJar, error) {
+	switch strings.ToLower(server.Loader) {
+	case "vanilla":
+		return GetVanillaServerJar(server)
+	case "paper":
+		return GetPaperMCServerJar("paper", server)
+	case "folia":
+		return GetPaperMCServerJar("folia", server)
+	case "velocity":
+		return GetPaperMCProxyJar("velocity", server)
+	case "waterfall":
+		return GetPaperMCProxyJar("waterfall", server)
+	case "bungeecord":
+		return GetBungeeCordServerJar(server)
+	case "purpur":
+		return GetPurpurServerJar(server)
+	case "leaf":
+		return GetLeafServerJar(server)
+	case "pufferfish":
+		return GetPufferfishServerJar(server)
+	case "fabric":
+		return GetFabricServerJar(server)
+	case "quilt":
+		return GetQuiltServerJar(server)
+	case "forge":
+		return GetForgeServerJar(server)
+	case "neoforge":
+		return GetNeoForgeServerJar(server)
+	default:
+		return nil, fmt.Errorf("unsupported server loader: %s", server.Loader)
+	}
+}
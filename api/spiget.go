@@ -0,0 +1,25 @@
+package api
+
+import "fmt"
+
+const SpigetApiUrl = "https://api.spiget.org/v2"
+
+// GetSpigetResource gets a SpigotMC resource from the Spiget API
+func GetSpigetResource(resourceId string) (*SpigetResource, error) {
+	var resource SpigetResource
+	err := get(fmt.Sprintf("%s/resources/%s", SpigetApiUrl, resourceId), &resource)
+	return &resource, err
+}
+
+// GetSpigetVersions gets the versions of a SpigotMC resource, newest first
+func GetSpigetVersions(resource *SpigetResource) ([]SpigetVersion, error) {
+	var versions []SpigetVersion
+	err := get(fmt.Sprintf("%s/resources/%d/versions?size=100&sort=-releaseDate", SpigetApiUrl, resource.ID), &versions)
+	return versions, err
+}
+
+// GetSpigetDownloadUrl gets the download URL of a version of a SpigotMC resource.
+// The URL always names the version, even for the latest one, so a locked URL keeps serving the same file.
+func GetSpigetDownloadUrl(resource *SpigetResource, version *SpigetVersion) string {
+	return fmt.Sprintf("%s/resources/%d/versions/%d/download", SpigetApiUrl, resource.ID, version.ID)
+}
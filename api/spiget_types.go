@@ -0,0 +1,27 @@
+package api
+
+type SpigetResource struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Tag      string `json:"tag"`
+	External bool   `json:"external"`
+	Premium  bool   `json:"premium"`
+	File     struct {
+		Type        string  `json:"type"`
+		Size        float64 `json:"size"`
+		SizeUnit    string  `json:"sizeUnit"`
+		URL         string  `json:"url"`
+		ExternalURL string  `json:"externalUrl"`
+	} `json:"file"`
+	Version struct {
+		ID int `json:"id"`
+	} `json:"version"`
+	TestedVersions []string `json:"testedVersions"`
+}
+
+type SpigetVersion struct {
+	ID          int    `json:"id"`
+	UUID        string `json:"uuid"`
+	Name        string `json:"name"`
+	ReleaseDate int64  `json:"releaseDate"`
+}
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/SKevo18/server_updater/api"
+	"github.com/SKevo18/server_updater/manifest"
+	"github.com/SKevo18/server_updater/source"
+	log "github.com/gwillem/go-simplelog"
+	"github.com/jlaffaye/ftp"
+	"github.com/spf13/cobra"
+)
+
+var frozen bool
+
+func init() {
+	installCmd.Flags().StringVarP(&configFilePath, "config", "c", "server_manifest.json", "Path to a manifest file")
+	installCmd.Flags().BoolVar(&allowIncompatible, "allow-incompatible", false, "Only warn about dependencies that are declared incompatible with each other (--frozen doesn't check compatibility, as the lock was checked when it was resolved)")
+	installCmd.Flags().BoolVar(&frozen, "frozen", false, "Install exactly what the lockfile records, without resolving any versions")
+	rootCmd.AddCommand(installCmd)
+}
+
+var installCmd = &cobra.Command{
+	Use:   "install [root_path]",
+	Short: "Installs server jar and plugins defined in manifest file. With --frozen, installs exactly what the lockfile records.",
+	Args:  cobra.RangeArgs(0, 1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !frozen {
+			return updateCmd.RunE(cmd, args)
+		}
+
+		var rootDir string
+		if len(args) == 0 {
+			rootDir = "."
+		} else {
+			rootDir = args[0]
+		}
+
+		// Read the manifest and the lock, which has to be resolved from the same manifest
+		m, data, err := readManifest(configFilePath)
+		if err != nil {
+			return err
+		}
+
+		lockPath := lockFilePath(configFilePath)
+		lock, err := readLock(lockPath)
+		if err != nil {
+			return fmt.Errorf("failed to read lockfile %s: %w", lockPath, err)
+		}
+		if lock.ManifestHash != manifestHash(data) {
+			return fmt.Errorf("lockfile %s is out of date with %s, run update to resolve it again", lockPath, configFilePath)
+		}
+
+		// FTP client
+		ftpClient, err := connectFTP(m.FTP)
+		if err != nil {
+			return err
+		}
+		if ftpClient != nil {
+			defer ftpClient.Quit()
+		}
+
+		// Read cache
+		cache := readCache(rootDir, ftpClient)
+		newCache := make(map[string]string)
+
+		// Install server jar
+		if lock.Server != nil {
+			if lock.Server.FileHash == "" {
+				return fmt.Errorf("no hash recorded for the %s server jar, run update to lock it again", lock.Server.Source)
+			}
+			log.Task(fmt.Sprintf("Installing %s server jar %s", lock.Server.Source, lock.Server.Version))
+			if err := placeServer(lockedServerJar(lock.Server), rootDir, ftpClient, cache, newCache); err != nil {
+				return err
+			}
+		}
+
+		// Install plugins and mods
+		if len(lock.Plugins) > 0 {
+			log.Task("Installing plugins")
+			if err := installLocked(lock.Plugins, m.Plugins, rootDir, ftpClient, cache, newCache); err != nil {
+				return err
+			}
+		}
+		if len(lock.Mods) > 0 {
+			log.Task("Installing mods")
+			if err := installLocked(lock.Mods, m.Mods, rootDir, ftpClient, cache, newCache); err != nil {
+				return err
+			}
+		}
+
+		// Write new cache
+		return writeCache(rootDir, ftpClient, newCache)
+	},
+}
+
+// installLocked downloads and places locked dependencies. Manifest dependencies are needed for their download headers.
+func installLocked(locked []manifest.LockedDependency, manifestDeps []manifest.Dependency, rootDir string, ftpClient *ftp.ServerConn, cache, newCache map[string]string) error {
+	for i := range locked {
+		dep := lockedDependency(&locked[i])
+		headers, err := lockedDownloadHeaders(&locked[i], manifestDeps)
+		if err != nil {
+			return err
+		}
+		dep.DownloadHeaders = headers
+
+		if dep.FileHash == "" {
+			return fmt.Errorf("no hash recorded for %s, run update to lock it again", locked[i].Path)
+		}
+
+		if err := downloadAndPlace(dep, filepath.Dir(locked[i].Path), rootDir, ftpClient, cache, newCache); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lockedDependency converts a locked dependency back to a resolved dependency, saved at its locked path
+func lockedDependency(locked *manifest.LockedDependency) *manifest.Dependency {
+	return &manifest.Dependency{
+		SaveAs:      filepath.Base(locked.Path),
+		ProjectId:   locked.ProjectId,
+		Version:     locked.Version,
+		FileName:    locked.FileName,
+		FileHash:    locked.FileHash,
+		DownloadUrl: locked.DownloadUrl,
+	}
+}
+
+// lockedDownloadHeaders rebuilds the download headers of a locked dependency from the source metadata of its manifest entry
+func lockedDownloadHeaders(locked *manifest.LockedDependency, manifestDeps []manifest.Dependency) (map[string]string, error) {
+	if locked.ManifestIndex == nil {
+		return nil, nil
+	}
+	if *locked.ManifestIndex < 0 || *locked.ManifestIndex >= len(manifestDeps) {
+		return nil, fmt.Errorf("lockfile refers to a missing manifest entry for %s", locked.Path)
+	}
+
+	src, _, meta, err := dependencySource(&manifestDeps[*locked.ManifestIndex])
+	if err != nil {
+		return nil, err
+	}
+	authenticator, ok := src.(source.Authenticator)
+	if !ok {
+		return nil, nil
+	}
+	return authenticator.DownloadHeaders(meta, locked.DownloadUrl)
+}
+
+// lockedServerJar converts a locked server back to a resolved server jar
+func lockedServerJar(locked *manifest.LockedServer) *api.ServerJar {
+	dep := lockedDependency(&locked.LockedDependency)
+	dep.SaveAs = locked.Path
+
+	return &api.ServerJar{
+		Dependency:     dep,
+		InstallerArgs:  locked.InstallerArgs,
+		LauncherFile:   locked.LauncherFile,
+		PreservedFiles: locked.PreservedFiles,
+	}
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/SKevo18/server_updater/manifest"
+)
+
+// lockFilePath returns the path of the lockfile next to the manifest, e.g. "server_manifest.lock.json"
+func lockFilePath(manifestPath string) string {
+	return strings.TrimSuffix(manifestPath, filepath.Ext(manifestPath)) + ".lock.json"
+}
+
+// manifestHash hashes the manifest contents, so a lock can be checked against the manifest it was resolved from
+func manifestHash(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// lockDependency records a placed dependency in the lock
+func lockDependency(lock *manifest.Lock, m *manifest.Manifest, depType, sourceType string, dep *manifest.Dependency, path string) {
+	locked := manifest.LockedDependency{
+		Source:      sourceType,
+		ProjectId:   dep.ProjectId,
+		Version:     dep.Version,
+		DownloadUrl: dep.DownloadUrl,
+		FileName:    dep.FileName,
+		FileHash:    dep.FileHash,
+		Path:        filepath.ToSlash(path),
+	}
+
+	manifestDeps := m.Plugins
+	if depType == "mods" {
+		manifestDeps = m.Mods
+	}
+	for i := range manifestDeps {
+		if &manifestDeps[i] == dep {
+			locked.ManifestIndex = &i
+			break
+		}
+	}
+
+	if depType == "mods" {
+		lock.Mods = append(lock.Mods, locked)
+	} else {
+		lock.Plugins = append(lock.Plugins, locked)
+	}
+}
+
+func readLock(path string) (*manifest.Lock, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var lock manifest.Lock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, err
+	}
+	return &lock, nil
+}
+
+func writeLock(path string, lock *manifest.Lock) error {
+	data, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
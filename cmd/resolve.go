@@ -0,0 +1,456 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/SKevo18/server_updater/manifest"
+	"github.com/SKevo18/server_updater/source"
+	log "github.com/gwillem/go-simplelog"
+)
+
+// resolvedDependency is a dependency whose version and files are resolved, but not downloaded yet
+type resolvedDependency struct {
+	dep        *manifest.Dependency
+	depType    string
+	src        source.Source
+	sourceType string
+	project    *source.Project
+	versions   []*source.Version
+	version    *source.Version
+	artifacts  []*source.Artifact
+
+	// Names of the dependents that led to the dependency, empty if it's defined in the manifest
+	chain []string
+
+	// Pin the version was resolved with, nil if any version was allowed
+	pin *versionPin
+}
+
+// key identifies the resolved project across sources
+func (d *resolvedDependency) key() string {
+	return projectKey(d.sourceType, d.project.ID)
+}
+
+// versionPin is an exact version of a project that a dependent requires
+type versionPin struct {
+	versionId string
+	chain     []string
+}
+
+// requirement is a dependency that still has to be resolved
+type requirement struct {
+	dep     *manifest.Dependency
+	depType string
+	chain   []string
+
+	// Optional dependencies selected by the dependent, which only applies to its direct dependencies
+	selected manifest.OptionalDependencies
+}
+
+// errRestart restarts the resolution after a dependency that was already resolved got pinned to another version,
+// or turned out to be embedded in another dependency
+var errRestart = errors.New("dependency has to be resolved again")
+
+// resolver collects the complete dependency graph before anything is downloaded,
+// so that dependents requiring different versions of the same project are reported up front
+type resolver struct {
+	m *manifest.Manifest
+
+	// Project IDs and slugs defined in the manifest, by project key
+	manifestProjectIds map[string]bool
+
+	// Pins of dependencies that were resolved before they got pinned, by project key, kept across restarts
+	pins map[string]*versionPin
+
+	// Names of the dependents that embed a project, by project key, kept across restarts
+	embedded map[string]string
+
+	// Resolved dependencies by project key
+	resolved map[string]*resolvedDependency
+	order    []*resolvedDependency
+}
+
+// resolveDependencies resolves the manifest dependencies and all of their dependencies, in the order they should be placed
+func resolveDependencies(roots []requirement, m *manifest.Manifest, manifestProjectIds map[string]bool) ([]*resolvedDependency, error) {
+	r := &resolver{m: m, manifestProjectIds: manifestProjectIds, pins: make(map[string]*versionPin), embedded: make(map[string]string)}
+	for {
+		r.resolved = make(map[string]*resolvedDependency)
+		r.order = nil
+
+		err := r.resolveAll(roots)
+		if errors.Is(err, errRestart) {
+			log.Debug("Resolving dependencies again with the pinned and embedded versions")
+			continue
+		}
+		return r.order, err
+	}
+}
+
+// resolveAll resolves the requirements breadth first, so manifest dependencies are resolved before any dependency of them
+func (r *resolver) resolveAll(queue []requirement) error {
+	for len(queue) > 0 {
+		req := queue[0]
+		queue = queue[1:]
+
+		dependencies, err := r.resolve(req)
+		if err != nil {
+			return err
+		}
+		queue = append(queue, dependencies...)
+	}
+	return nil
+}
+
+// resolve resolves a single requirement, returning the requirements of its dependencies
+func (r *resolver) resolve(req requirement) ([]requirement, error) {
+	dep := req.dep
+
+	// Dependencies that are already resolved only have to agree on the version
+	if dep.ProjectId != "" {
+		key := projectKey(dependencySourceType(dep), dep.ProjectId)
+		if existing, ok := r.resolved[key]; ok {
+			return nil, r.checkPin(existing, req)
+		}
+		if len(req.chain) > 0 && r.manifestProjectIds[key] {
+			log.Debug(fmt.Sprintf("Skipping dependency %s (project ID: %s) as it's already defined in manifest", dep.SaveAs, dep.ProjectId))
+			return nil, nil
+		}
+		if embeddedIn, ok := r.embedded[key]; ok && len(req.chain) > 0 {
+			log.Debug(fmt.Sprintf("Skipping dependency %s as it's embedded in %s", dep.ProjectId, embeddedIn))
+			return nil, nil
+		}
+	}
+
+	src, sourceType, sourceMeta, err := dependencySource(dep)
+	if err != nil {
+		return nil, err
+	}
+	if src == nil {
+		return nil, nil
+	}
+
+	if dep.Channel == "" {
+		dep.Channel = r.m.Channel
+	}
+	dep.Channel = strings.ToLower(dep.Channel)
+	if !source.IsChannel(dep.Channel) {
+		return nil, fmt.Errorf("invalid channel '%s' for %s, expected release, beta or alpha", dep.Channel, dep.SaveAs)
+	}
+
+	// Optional dependencies without a project ID (e.g. from Hangar) are selected by slug before their project is looked up
+	optional := dep.DependencyType == manifest.DependencyOptional
+	slug := extractProjectId(dep)
+	if optional && dep.ProjectId == "" && !req.selected.Includes(slug) {
+		log.Debug(fmt.Sprintf("Skipping optional dependency %s of %s", slug, formatChain(req.chain)))
+		return nil, nil
+	}
+
+	project, err := src.ResolveProject(dep, sourceMeta)
+	if err != nil && optional {
+		log.Debug(fmt.Sprintf("Skipping optional dependency %s of %s: %s", slug, formatChain(req.chain), err))
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s project for %s: %w", sourceType, dep.SaveAs, err)
+	}
+	if existing, ok := r.resolved[projectKey(sourceType, project.ID)]; ok {
+		return nil, r.checkPin(existing, req)
+	}
+	if optional && !req.selected.Includes(dep.ProjectId, project.ID, project.Slug) {
+		log.Debug(fmt.Sprintf("Skipping optional dependency %s of %s", project.Name, formatChain(req.chain)))
+		return nil, nil
+	}
+
+	log.Task(fmt.Sprintf("Resolving %s: %s", req.depType, project.Name))
+
+	versions, err := src.ListVersions(project, r.m.Server, dep.DownloadIncompatible)
+	if err != nil {
+		log.Warn(fmt.Sprintf("No compatible versions found for %s", project.Name))
+		return nil, nil // Continue with next dependency
+	}
+
+	// A dependent may require an exact version
+	pin := r.pins[projectKey(sourceType, project.ID)]
+	if pin == nil && dep.VersionId != "" {
+		pin = &versionPin{versionId: dep.VersionId, chain: req.chain}
+	}
+
+	var version *source.Version
+	if pin != nil {
+		version, err = pinnedVersion(src, project, versions, pin)
+		if err != nil {
+			return nil, err
+		}
+		if version == nil {
+			log.Warn(fmt.Sprintf("Version %s of %s required by %s not found", pin.versionId, project.Name, formatChain(pin.chain)))
+			return nil, nil // Continue with next dependency
+		}
+		if !slices.Contains(versions, version) {
+			versions = append(versions, version)
+		}
+	} else {
+		if len(versions) == 0 {
+			log.Warn(fmt.Sprintf("No compatible versions found for %s", project.Name))
+			return nil, nil // Continue with next dependency
+		}
+		version = source.SelectVersion(versions, dep.WantedVersion, dep.Channel)
+		if version == nil && dep.DependencyType == manifest.DependencyRequired {
+			// Dependents don't work without their required dependencies, so these fall back to less stable versions
+			if version = source.SelectVersion(versions, dep.WantedVersion, ""); version != nil {
+				log.Warn(fmt.Sprintf("No %s version of %s found, using %s version %s required by %s",
+					dep.Channel, project.Name, version.Channel, version.Number, formatChain(req.chain)))
+			}
+		}
+		if version == nil {
+			log.Warn(fmt.Sprintf("Wanted version '%s' not found for %s", dep.WantedVersion, project.Name))
+			return nil, nil // Continue with next dependency
+		}
+	}
+
+	artifacts, err := src.Artifacts(dep, project, version, r.m.Server)
+	if err != nil {
+		return nil, err
+	}
+	if len(artifacts) == 0 {
+		log.Warn(fmt.Sprintf("No primary file found for %s", project.Name))
+		return nil, nil // Continue with next dependency
+	}
+
+	dep.ProjectId = project.ID
+	dep.Version = version.Number
+	dep.FileName = artifacts[0].FileName
+	dep.FileHash = artifacts[0].FileHash
+	dep.DownloadUrl = artifacts[0].DownloadUrl
+	dep.DownloadHeaders = artifacts[0].DownloadHeaders
+
+	resolved := &resolvedDependency{
+		dep:        dep,
+		depType:    req.depType,
+		src:        src,
+		sourceType: sourceType,
+		project:    project,
+		versions:   versions,
+		version:    version,
+		artifacts:  artifacts,
+		chain:      req.chain,
+		pin:        pin,
+	}
+	r.resolved[resolved.key()] = resolved
+	r.order = append(r.order, resolved)
+
+	// Other dependencies
+	dep.Dependencies, err = src.Dependencies(project, version, r.m.Server, dep.DownloadIncompatible)
+	if err != nil {
+		return nil, err
+	}
+
+	chain := append(req.chain[:len(req.chain):len(req.chain)], project.Name)
+	requirements := make([]requirement, 0, len(dep.Dependencies))
+	for _, subDep := range dep.Dependencies {
+		switch subDep.DependencyType {
+		case manifest.DependencyEmbedded:
+			if err := r.embed(subDep, project.Name); err != nil {
+				return nil, err
+			}
+			continue
+		case manifest.DependencyOptional:
+			if dep.OptionalDependencies.IsNone() {
+				continue
+			}
+		}
+
+		// Inherit from parent
+		if subDep.Channel == "" {
+			subDep.Channel = dep.Channel
+		}
+
+		requirements = append(requirements, requirement{dep: subDep, depType: req.depType, chain: chain, selected: dep.OptionalDependencies})
+	}
+	return requirements, nil
+}
+
+// embed records that a project is embedded in a dependent, so it isn't installed separately.
+// Projects that are defined in the manifest are still installed.
+func (r *resolver) embed(dep *manifest.Dependency, dependent string) error {
+	key := projectKey(dependencySourceType(dep), dep.ProjectId)
+	if _, ok := r.embedded[key]; ok || dep.ProjectId == "" {
+		return nil
+	}
+	r.embedded[key] = dependent
+
+	existing, ok := r.resolved[key]
+	if !ok {
+		return nil
+	}
+	if len(existing.chain) == 0 {
+		log.Warn(fmt.Sprintf("%s is embedded in %s, but also defined in the manifest", existing.project.Name, dependent))
+		return nil
+	}
+	return errRestart
+}
+
+// checkPin checks that a requirement agrees with the version a dependency was already resolved to.
+// Dependencies that were freely resolved are resolved again if a dependent pins another version of them,
+// which for manifest dependencies has to satisfy the wanted version.
+func (r *resolver) checkPin(existing *resolvedDependency, req requirement) error {
+	versionId := req.dep.VersionId
+	if versionId == "" || versionId == existing.version.ID {
+		return nil
+	}
+
+	pin := &versionPin{versionId: versionId, chain: req.chain}
+	switch {
+	case existing.pin != nil:
+		return fmt.Errorf("conflicting versions of %s: %s requires %s, but %s requires %s",
+			existing.project.Name,
+			formatChain(existing.pin.chain), existing.version.Number,
+			formatChain(req.chain), formatVersion(existing.versions, versionId))
+	case len(existing.chain) == 0:
+		// Manifest dependencies can still be pinned to a version that satisfies the wanted version
+		version, err := pinnedVersion(existing.src, existing.project, existing.versions, pin)
+		if err != nil {
+			return err
+		}
+		if version == nil || !source.MatchesVersion(version, existing.dep.WantedVersion, existing.dep.Channel) {
+			return fmt.Errorf("conflicting versions of %s: the manifest wants %s, but %s requires %s",
+				existing.project.Name, existing.dep.WantedVersion,
+				formatChain(req.chain), formatVersion(existing.versions, versionId))
+		}
+	}
+
+	r.pins[existing.key()] = pin
+	return errRestart
+}
+
+// pinnedVersion finds a pinned version among the listed versions, or fetches it if the source can.
+// Dependents know best which version they work with, so pinned versions are installed even if they aren't listed as compatible.
+func pinnedVersion(src source.Source, project *source.Project, versions []*source.Version, pin *versionPin) (*source.Version, error) {
+	if version := source.FindVersionById(versions, pin.versionId); version != nil {
+		return version, nil
+	}
+
+	fetcher, ok := src.(source.VersionFetcher)
+	if !ok {
+		return nil, nil
+	}
+
+	log.Debug(fmt.Sprintf("Fetching version %s of %s required by %s", pin.versionId, project.Name, formatChain(pin.chain)))
+	version, err := fetcher.FetchVersion(project, pin.versionId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get version %s of %s required by %s: %w", pin.versionId, project.Name, formatChain(pin.chain), err)
+	}
+	return version, nil
+}
+
+// checkIncompatibilities reports resolved dependencies that declare each other incompatible.
+// Incompatibilities fail the update, unless they are allowed, in which case they are only warned about.
+// Pairs that declare each other incompatible are only reported once.
+func checkIncompatibilities(resolved []*resolvedDependency, allow bool) error {
+	var conflicts []string
+	reported := make(map[[2]string]bool)
+	for _, dep := range resolved {
+		src, ok := dep.src.(source.IncompatibilitySource)
+		if !ok {
+			continue
+		}
+
+		incompatibilities, err := src.Incompatibilities(dep.project, dep.version)
+		if err != nil {
+			return err
+		}
+		for _, incompatibility := range incompatibilities {
+			for _, other := range resolved {
+				if other == dep || other.sourceType != dep.sourceType {
+					continue
+				}
+				if incompatibility.ProjectId != "" && incompatibility.ProjectId != other.project.ID {
+					continue
+				}
+				if incompatibility.VersionId != "" && incompatibility.VersionId != other.version.ID {
+					continue
+				}
+
+				pair := [2]string{dep.key(), other.key()}
+				if pair[0] > pair[1] {
+					pair[0], pair[1] = pair[1], pair[0]
+				}
+				if reported[pair] {
+					continue
+				}
+				reported[pair] = true
+
+				conflict := fmt.Sprintf("%s %s (required by %s) is incompatible with %s %s (required by %s)",
+					dep.project.Name, dep.version.Number, formatChain(dep.chain),
+					other.project.Name, other.version.Number, formatChain(other.chain))
+				log.Warn(conflict)
+				conflicts = append(conflicts, conflict)
+			}
+		}
+	}
+
+	if len(conflicts) > 0 && !allow {
+		return fmt.Errorf("incompatible dependencies, use --allow-incompatible to install them anyway:\n%s", strings.Join(conflicts, "\n"))
+	}
+	return nil
+}
+
+// projectKey identifies a project across sources, as project IDs are only unique within their source
+func projectKey(sourceType, projectId string) string {
+	return sourceType + ":" + projectId
+}
+
+// dependencySourceType gets the name of a dependency's source from its "source.<name>" metadata block,
+// empty if it has none
+func dependencySourceType(dep *manifest.Dependency) string {
+	for sourceKey := range dep.Metadata {
+		if sourceType, ok := strings.CutPrefix(sourceKey, "source."); ok {
+			return sourceType
+		}
+	}
+	return ""
+}
+
+// dependencySource gets the source of a dependency from its "source.<name>" metadata block.
+// Returns a nil source if the dependency has no known source.
+func dependencySource(dep *manifest.Dependency) (source.Source, string, map[string]any, error) {
+	for sourceKey, meta := range dep.Metadata {
+		if !strings.HasPrefix(sourceKey, "source.") {
+			continue
+		}
+
+		sourceType := strings.TrimPrefix(sourceKey, "source.")
+		src, ok := source.Get(sourceType)
+		if !ok {
+			log.Warn(fmt.Sprintf("Unknown dependency source: %s", sourceType))
+			return nil, "", nil, nil
+		}
+
+		sourceMeta, ok := meta.(map[string]any)
+		if !ok {
+			return nil, "", nil, fmt.Errorf("invalid %s metadata format for %s", sourceType, dep.SaveAs)
+		}
+		return src, sourceType, sourceMeta, nil
+	}
+
+	log.Warn(fmt.Sprintf("No source found for dependency with SaveAs: %s", dep.SaveAs))
+	return nil, "", nil, nil
+}
+
+// formatChain formats the dependents that led to a dependency, e.g. "Create -> Flywheel"
+func formatChain(chain []string) string {
+	if len(chain) == 0 {
+		return "the manifest"
+	}
+	return strings.Join(chain, " -> ")
+}
+
+// formatVersion formats a version ID as its version number, if the version is known
+func formatVersion(versions []*source.Version, versionId string) string {
+	if version := source.FindVersionById(versions, versionId); version != nil {
+		return version.Number
+	}
+	return versionId
+}
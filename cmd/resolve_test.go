@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/SKevo18/server_updater/manifest"
+	"github.com/SKevo18/server_updater/source"
+)
+
+// fakeProject is a project of the fake source, with its versions newest first
+type fakeProject struct {
+	versions []string
+
+	// Dependencies by version number
+	dependencies map[string][]fakeDependency
+}
+
+// fakeDependency is a dependency declared by a version of a fake project
+type fakeDependency struct {
+	projectId      string
+	versionId      string
+	dependencyType string
+}
+
+// fakeSource resolves projects by their "projectId" metadata. Version IDs are "<projectId>@<number>".
+type fakeSource map[string]*fakeProject
+
+func (s fakeSource) ResolveProject(dep *manifest.Dependency, meta map[string]any) (*source.Project, error) {
+	projectId, _ := meta["projectId"].(string)
+	project, ok := s[projectId]
+	if !ok {
+		return nil, fmt.Errorf("project %s not found", projectId)
+	}
+	return &source.Project{ID: projectId, Name: projectId, Data: project}, nil
+}
+
+func (s fakeSource) ListVersions(project *source.Project, server manifest.Server, incompatible bool) ([]*source.Version, error) {
+	var versions []*source.Version
+	for _, number := range project.Data.(*fakeProject).versions {
+		versions = append(versions, &source.Version{ID: project.ID + "@" + number, Number: number, Channel: source.ChannelRelease})
+	}
+	return versions, nil
+}
+
+func (s fakeSource) Artifacts(dep *manifest.Dependency, project *source.Project, version *source.Version, server manifest.Server) ([]*source.Artifact, error) {
+	fileName := fmt.Sprintf("%s-%s.jar", project.ID, version.Number)
+	return []*source.Artifact{{FileName: fileName, DownloadUrl: "https://example.com/" + fileName}}, nil
+}
+
+func (s fakeSource) Dependencies(project *source.Project, version *source.Version, server manifest.Server, incompatible bool) ([]*manifest.Dependency, error) {
+	var deps []*manifest.Dependency
+	for _, dep := range project.Data.(*fakeProject).dependencies[version.Number] {
+		deps = append(deps, fakeDep(dep.projectId, "@latest", dep.versionId, dep.dependencyType))
+	}
+	return deps, nil
+}
+
+func fakeDep(projectId, wantedVersion, versionId, dependencyType string) *manifest.Dependency {
+	return &manifest.Dependency{
+		SaveAs:         projectId,
+		WantedVersion:  wantedVersion,
+		ProjectId:      projectId,
+		VersionId:      versionId,
+		DependencyType: dependencyType,
+		Metadata:       map[string]any{"source.fake": map[string]any{"projectId": projectId}},
+	}
+}
+
+func TestResolveDependencies(t *testing.T) {
+	tests := []struct {
+		name     string
+		projects fakeSource
+
+		// Manifest plugins as project ID and wanted version
+		plugins [][2]string
+
+		// Projects and manifest plugins of a second source
+		otherProjects fakeSource
+		otherPlugins  [][2]string
+
+		// Resolved versions by project ID, in the order they are placed
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "pin discovered after free resolution",
+			projects: fakeSource{
+				"a":   {versions: []string{"1.0"}, dependencies: map[string][]fakeDependency{"1.0": {{projectId: "lib", dependencyType: manifest.DependencyRequired}}}},
+				"b":   {versions: []string{"1.0"}, dependencies: map[string][]fakeDependency{"1.0": {{projectId: "lib", versionId: "lib@1.0", dependencyType: manifest.DependencyRequired}}}},
+				"lib": {versions: []string{"2.0", "1.0"}},
+			},
+			plugins: [][2]string{{"a", "@latest"}, {"b", "@latest"}},
+			want:    []string{"a 1.0", "b 1.0", "lib 1.0"},
+		},
+		{
+			name: "conflicting pins",
+			projects: fakeSource{
+				"a":   {versions: []string{"1.0"}, dependencies: map[string][]fakeDependency{"1.0": {{projectId: "lib", versionId: "lib@1.0", dependencyType: manifest.DependencyRequired}}}},
+				"b":   {versions: []string{"1.0"}, dependencies: map[string][]fakeDependency{"1.0": {{projectId: "lib", versionId: "lib@2.0", dependencyType: manifest.DependencyRequired}}}},
+				"lib": {versions: []string{"2.0", "1.0"}},
+			},
+			plugins: [][2]string{{"a", "@latest"}, {"b", "@latest"}},
+			wantErr: "conflicting versions of lib: a requires 1.0, but b requires 2.0",
+		},
+		{
+			name: "pin satisfying the manifest's latest version",
+			projects: fakeSource{
+				"a":   {versions: []string{"1.0"}, dependencies: map[string][]fakeDependency{"1.0": {{projectId: "lib", versionId: "lib@1.0", dependencyType: manifest.DependencyRequired}}}},
+				"lib": {versions: []string{"2.0", "1.0"}},
+			},
+			plugins: [][2]string{{"lib", "@latest"}, {"a", "@latest"}},
+			want:    []string{"lib 1.0", "a 1.0"},
+		},
+		{
+			name: "pin satisfying the manifest's version range",
+			projects: fakeSource{
+				"a":   {versions: []string{"1.0"}, dependencies: map[string][]fakeDependency{"1.0": {{projectId: "lib", versionId: "lib@1.1", dependencyType: manifest.DependencyRequired}}}},
+				"lib": {versions: []string{"2.0", "1.2", "1.1"}},
+			},
+			plugins: [][2]string{{"lib", "^1.1"}, {"a", "@latest"}},
+			want:    []string{"lib 1.1", "a 1.0"},
+		},
+		{
+			name: "pin against the manifest's exact version",
+			projects: fakeSource{
+				"a":   {versions: []string{"1.0"}, dependencies: map[string][]fakeDependency{"1.0": {{projectId: "lib", versionId: "lib@1.0", dependencyType: manifest.DependencyRequired}}}},
+				"lib": {versions: []string{"2.0", "1.0"}},
+			},
+			plugins: [][2]string{{"lib", "2.0"}, {"a", "@latest"}},
+			wantErr: "conflicting versions of lib: the manifest wants 2.0, but a requires 1.0",
+		},
+		{
+			name: "embedded project discovered after it was resolved",
+			projects: fakeSource{
+				"a":      {versions: []string{"1.0"}, dependencies: map[string][]fakeDependency{"1.0": {{projectId: "lib", dependencyType: manifest.DependencyRequired}}}},
+				"b":      {versions: []string{"1.0"}, dependencies: map[string][]fakeDependency{"1.0": {{projectId: "shaded", dependencyType: manifest.DependencyRequired}}}},
+				"shaded": {versions: []string{"1.0"}, dependencies: map[string][]fakeDependency{"1.0": {{projectId: "lib", dependencyType: manifest.DependencyEmbedded}}}},
+				"lib":    {versions: []string{"1.0"}},
+			},
+			plugins: [][2]string{{"a", "@latest"}, {"b", "@latest"}},
+			want:    []string{"a 1.0", "b 1.0", "shaded 1.0"},
+		},
+		{
+			name:          "same project ID in different sources",
+			projects:      fakeSource{"1234": {versions: []string{"1.0"}}},
+			plugins:       [][2]string{{"1234", "@latest"}},
+			otherProjects: fakeSource{"1234": {versions: []string{"2.0"}}},
+			otherPlugins:  [][2]string{{"1234", "@latest"}},
+			want:          []string{"1234 1.0", "1234 2.0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source.Register("fake", tt.projects)
+			source.Register("other", tt.otherProjects)
+
+			m := &manifest.Manifest{}
+			for _, plugin := range tt.plugins {
+				m.Plugins = append(m.Plugins, *fakeDep(plugin[0], plugin[1], "", ""))
+			}
+			for _, plugin := range tt.otherPlugins {
+				dep := fakeDep(plugin[0], plugin[1], "", "")
+				dep.Metadata = map[string]any{"source.other": dep.Metadata["source.fake"]}
+				m.Plugins = append(m.Plugins, *dep)
+			}
+			roots := make([]requirement, 0, len(m.Plugins))
+			for i := range m.Plugins {
+				roots = append(roots, requirement{dep: &m.Plugins[i], depType: "plugins"})
+			}
+
+			resolved, err := resolveDependencies(roots, m, buildManifestProjectIdMap(m))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := make([]string, 0, len(resolved))
+			for _, dep := range resolved {
+				got = append(got, dep.project.ID+" "+dep.version.Number)
+			}
+			if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
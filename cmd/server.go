@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/SKevo18/server_updater/api"
+	"github.com/SKevo18/server_updater/manifest"
+	log "github.com/gwillem/go-simplelog"
+	"github.com/jlaffaye/ftp"
+)
+
+// serverProjectId is the cache project ID shared by all server files, so switching loaders purges the old server too
+const serverProjectId = "server"
+
+func processServer(m *manifest.Manifest, rootDir string, ftpClient *ftp.ServerConn, cache, newCache map[string]string, lock *manifest.Lock) error {
+	hasLauncher := api.HasLauncher(m.Server.Loader)
+	if m.Server.LoaderFile == "" && hasLauncher {
+		log.Warn("No loaderFile defined in manifest, skipping server jar")
+		return nil
+	}
+	if m.Server.LoaderFile != "" && !hasLauncher {
+		log.Warn(fmt.Sprintf("%s is started with the run.sh or run.bat script it installs, loaderFile is ignored", m.Server.Loader))
+	}
+
+	serverJar, err := api.GetServerJar(m.Server)
+	if err != nil {
+		return err
+	}
+
+	// Installers without a launcher place no loader file
+	var path string
+	if hasLauncher {
+		path = serverJar.CanonicalFileName()
+	}
+
+	// Lock the loader's project before placing the jar records it under the shared server project ID
+	locked := &manifest.LockedServer{
+		LockedDependency: manifest.LockedDependency{
+			Source:      m.Server.Loader,
+			ProjectId:   serverJar.ProjectId,
+			Version:     serverJar.Version,
+			DownloadUrl: serverJar.DownloadUrl,
+			FileName:    serverJar.FileName,
+			FileHash:    serverJar.FileHash,
+			Path:        path,
+		},
+		InstallerArgs:  serverJar.InstallerArgs,
+		LauncherFile:   serverJar.LauncherFile,
+		PreservedFiles: serverJar.PreservedFiles,
+	}
+
+	if err := placeServer(serverJar, rootDir, ftpClient, cache, newCache); err != nil {
+		return err
+	}
+	locked.FileHash = serverJar.FileHash // Computed while placing, if the loader publishes none
+	lock.Server = locked
+	return nil
+}
+
+// placeServer downloads the server jar, or runs the installer, and purges the previous server
+func placeServer(serverJar *api.ServerJar, rootDir string, ftpClient *ftp.ServerConn, cache, newCache map[string]string) error {
+	var err error
+	if serverJar.IsInstaller() {
+		err = installServer(serverJar, rootDir, ftpClient, cache, newCache)
+	} else {
+		serverJar.ProjectId = serverProjectId
+		err = downloadAndPlace(serverJar.Dependency, ".", rootDir, ftpClient, cache, newCache)
+	}
+	if err != nil {
+		return err
+	}
+
+	purgeStale(serverProjectId, ftpClient, cache, newCache)
+	return nil
+}
+
+// installServer runs a server installer in a staging directory and places everything it produced.
+// Installed files are cached per loader version, so the installer only runs again when the version changes.
+func installServer(serverJar *api.ServerJar, rootDir string, ftpClient *ftp.ServerConn, cache, newCache map[string]string) error {
+	installId := fmt.Sprintf("%s:%s-%s", serverProjectId, serverJar.ProjectId, serverJar.Version)
+	installerPath := filepath.Join(os.TempDir(), serverJar.FileName)
+
+	// Installers without a published hash are downloaded every time, so the lock records the hash of their contents
+	downloaded := false
+	if serverJar.FileHash == "" {
+		log.Task(fmt.Sprintf("Downloading %s", serverJar.FileName))
+		if err := api.DownloadFile(serverJar.DownloadUrl, installerPath); err != nil {
+			return err
+		}
+		defer os.Remove(installerPath)
+
+		fileHash, err := api.HashFile(installerPath)
+		if err != nil {
+			return err
+		}
+		serverJar.FileHash = fileHash
+		downloaded = true
+	}
+
+	// Check cache
+	var installed bool
+	for cacheKey, oldFile := range cache {
+		if strings.HasPrefix(cacheKey, installId+":") {
+			newCache[cacheKey] = oldFile
+			installed = true
+		}
+	}
+	if installed {
+		log.Debug(fmt.Sprintf("%s %s is already installed", serverJar.ProjectId, serverJar.Version))
+		return nil
+	}
+
+	// Download installer
+	if !downloaded {
+		log.Task(fmt.Sprintf("Downloading %s", serverJar.FileName))
+		if err := api.DownloadFile(serverJar.DownloadUrl, installerPath); err != nil {
+			return err
+		}
+		defer os.Remove(installerPath)
+
+		if err := api.VerifyFile(installerPath, serverJar.FileHash); err != nil {
+			return err
+		}
+	}
+
+	stagingDir, err := os.MkdirTemp("", "server_updater-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(stagingDir)
+
+	// Run installer
+	args := []string{"-jar", installerPath}
+	for _, arg := range serverJar.InstallerArgs {
+		args = append(args, strings.ReplaceAll(arg, "{installDir}", stagingDir))
+	}
+
+	log.Task(fmt.Sprintf("Installing %s %s", serverJar.ProjectId, serverJar.Version))
+	installer := exec.Command("java", args...)
+	installer.Dir = stagingDir
+	if output, err := installer.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to run %s: %w\n%s", serverJar.FileName, err, strings.TrimSpace(string(output)))
+	}
+
+	// Files that are already on the server from a previous installation
+	oldFiles := make(map[string]bool, len(cache))
+	for _, oldFile := range cache {
+		oldFiles[oldFile] = true
+	}
+
+	// Place installed files
+	return filepath.WalkDir(stagingDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		finalPath, err := filepath.Rel(stagingDir, path)
+		if err != nil {
+			return err
+		}
+		if strings.HasSuffix(finalPath, ".log") {
+			return nil
+		}
+		if finalPath == serverJar.LauncherFile {
+			finalPath = serverJar.CanonicalFileName()
+		}
+
+		newCache[fmt.Sprintf("%s:%s", installId, filepath.ToSlash(finalPath))] = finalPath
+		if slices.Contains(serverJar.PreservedFiles, filepath.ToSlash(finalPath)) && oldFiles[finalPath] {
+			log.Debug(fmt.Sprintf("Keeping existing %s", finalPath))
+			return nil
+		}
+
+		log.Debug(fmt.Sprintf("Placing %s", finalPath))
+		return placeFile(path, finalPath, rootDir, ftpClient)
+	})
+}
@@ -15,12 +15,16 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var configFilePath string
+var (
+	configFilePath    string
+	allowIncompatible bool
+)
 
 const cacheFileName = "updater_cache.json"
 
 func init() {
 	updateCmd.Flags().StringVarP(&configFilePath, "config", "c", "server_manifest.json", "Path to a manifest file")
+	updateCmd.Flags().BoolVar(&allowIncompatible, "allow-incompatible", false, "Only warn about dependencies that are declared incompatible with each other")
 	rootCmd.AddCommand(updateCmd)
 }
 
@@ -37,49 +41,31 @@ var updateCmd = &cobra.Command{
 		}
 
 		// Read the manifest
-		log.Task("Reading manifest file...")
-
-		// Read the file directly instead of using Viper's Unmarshal
-		data, err := os.ReadFile(configFilePath)
+		m, data, err := readManifest(configFilePath)
 		if err != nil {
-			return fmt.Errorf("failed to read config file: %w", err)
-		}
-
-		var m manifest.Manifest
-		if err := json.Unmarshal(data, &m); err != nil {
-			return fmt.Errorf("failed to unmarshal config: %w", err)
+			return err
 		}
-		log.Task("Manifest parsed successfully")
 
-		// FTP client
-		var ftpClient *ftp.ServerConn
-		if m.FTP != nil {
-			log.Task(fmt.Sprintf("Connecting to FTP server at %s", m.FTP.Host))
-			var err error
-			ftpClient, err = ftp.Dial(fmt.Sprintf("%s:%d", m.FTP.Host, m.FTP.Port))
+		// Resolve Minecraft version once, so every source sees the same version
+		minecraftVersion := m.Server.MinecraftVersion
+		if strings.HasPrefix(minecraftVersion, "@latest") {
+			minecraftVersion, err = api.ResolveMinecraftVersion(m.Server)
 			if err != nil {
 				return err
 			}
-			defer ftpClient.Quit()
+			log.Task(fmt.Sprintf("Resolved Minecraft version %s to %s", m.Server.MinecraftVersion, minecraftVersion))
+		}
+		m.SetMinecraftVersion(minecraftVersion)
 
-			if err := ftpClient.Login(m.FTP.Username, m.FTP.Password); err != nil {
-				return err
-			}
-			log.Task("FTP login successful")
-
-			// Change to remote directory if specified
-			if m.FTP.RemotePath != "" {
-				if err := ftpClient.ChangeDir(m.FTP.RemotePath); err != nil {
-					// Try to create the directory if it doesn't exist
-					if err := ftpClient.MakeDir(m.FTP.RemotePath); err != nil {
-						return fmt.Errorf("failed to create remote directory %s: %w", m.FTP.RemotePath, err)
-					}
-					if err := ftpClient.ChangeDir(m.FTP.RemotePath); err != nil {
-						return fmt.Errorf("failed to change to created remote directory %s: %w", m.FTP.RemotePath, err)
-					}
-				}
-				log.Task(fmt.Sprintf("Changed to remote directory: %s", m.FTP.RemotePath))
-			}
+		lock := &manifest.Lock{ManifestHash: manifestHash(data), MinecraftVersion: minecraftVersion}
+
+		// FTP client
+		ftpClient, err := connectFTP(m.FTP)
+		if err != nil {
+			return err
+		}
+		if ftpClient != nil {
+			defer ftpClient.Quit()
 		}
 
 		// Read cache
@@ -87,54 +73,124 @@ var updateCmd = &cobra.Command{
 		newCache := make(map[string]string)
 
 		// Build a map of all project IDs defined in the manifest for dependency precedence
-		manifestProjectIds := buildManifestProjectIdMap(&m)
-
-		// Process plugins
-		if m.HasPlugins() {
-			log.Task("Processing plugins")
-			plugins := make([]*manifest.Dependency, len(m.Plugins))
-			for i := range m.Plugins {
-				plugins[i] = &m.Plugins[i]
-			}
-			err := processDependencies(plugins, "plugins", &m, rootDir, ftpClient, cache, newCache, manifestProjectIds)
-			if err != nil {
+		manifestProjectIds := buildManifestProjectIdMap(m)
+
+		// Resolve plugins, mods and their dependencies before downloading anything
+		roots := make([]requirement, 0, len(m.Plugins)+len(m.Mods))
+		for i := range m.Plugins {
+			roots = append(roots, requirement{dep: &m.Plugins[i], depType: "plugins"})
+		}
+		for i := range m.Mods {
+			roots = append(roots, requirement{dep: &m.Mods[i], depType: "mods"})
+		}
+
+		log.Task("Resolving dependencies")
+		resolved, err := resolveDependencies(roots, m, manifestProjectIds)
+		if err != nil {
+			return err
+		}
+		if err := checkIncompatibilities(resolved, allowIncompatible); err != nil {
+			return err
+		}
+
+		// Process server jar
+		if m.Server.Loader != "" {
+			log.Task(fmt.Sprintf("Processing %s server jar", m.Server.Loader))
+			if err := processServer(m, rootDir, ftpClient, cache, newCache, lock); err != nil {
 				return err
 			}
 		}
 
-		// Process mods
-		if m.HasMods() {
-			log.Task("Processing mods")
-			mods := make([]*manifest.Dependency, len(m.Mods))
-			for i := range m.Mods {
-				mods[i] = &m.Mods[i]
-			}
-			err := processDependencies(mods, "mods", &m, rootDir, ftpClient, cache, newCache, manifestProjectIds)
-			if err != nil {
+		// Process plugins and mods
+		for _, dep := range resolved {
+			log.Task(fmt.Sprintf("Processing %s: %s", dep.depType, dep.project.Name))
+			if err := placeDependency(dep, m, rootDir, ftpClient, cache, newCache, lock); err != nil {
 				return err
 			}
 		}
 
 		// Write new cache
-		return writeCache(rootDir, ftpClient, newCache)
+		if err := writeCache(rootDir, ftpClient, newCache); err != nil {
+			return err
+		}
+
+		// Write lockfile next to the manifest
+		lockPath := lockFilePath(configFilePath)
+		log.Task(fmt.Sprintf("Writing lockfile %s", lockPath))
+		return writeLock(lockPath, lock)
 	},
 }
 
-// buildManifestProjectIdMap creates a map of all project IDs defined in the manifest
+// readManifest reads and parses the manifest, also returning its raw contents
+func readManifest(path string) (*manifest.Manifest, []byte, error) {
+	log.Task("Reading manifest file...")
+
+	// Read the file directly instead of using Viper's Unmarshal
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var m manifest.Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	log.Task("Manifest parsed successfully")
+	return &m, data, nil
+}
+
+// connectFTP connects to the FTP server defined in the manifest and changes to its remote path.
+// Returns nil if no FTP server is defined.
+func connectFTP(config *manifest.FTP) (*ftp.ServerConn, error) {
+	if config == nil {
+		return nil, nil
+	}
+
+	log.Task(fmt.Sprintf("Connecting to FTP server at %s", config.Host))
+	ftpClient, err := ftp.Dial(fmt.Sprintf("%s:%d", config.Host, config.Port))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ftpClient.Login(config.Username, config.Password); err != nil {
+		ftpClient.Quit()
+		return nil, err
+	}
+	log.Task("FTP login successful")
+
+	// Change to remote directory if specified
+	if config.RemotePath != "" {
+		if err := ftpClient.ChangeDir(config.RemotePath); err != nil {
+			// Try to create the directory if it doesn't exist
+			if err := ftpClient.MakeDir(config.RemotePath); err != nil {
+				ftpClient.Quit()
+				return nil, fmt.Errorf("failed to create remote directory %s: %w", config.RemotePath, err)
+			}
+			if err := ftpClient.ChangeDir(config.RemotePath); err != nil {
+				ftpClient.Quit()
+				return nil, fmt.Errorf("failed to change to created remote directory %s: %w", config.RemotePath, err)
+			}
+		}
+		log.Task(fmt.Sprintf("Changed to remote directory: %s", config.RemotePath))
+	}
+	return ftpClient, nil
+}
+
+// buildManifestProjectIdMap creates a map of all project IDs defined in the manifest, by project key
 func buildManifestProjectIdMap(m *manifest.Manifest) map[string]bool {
 	projectIds := make(map[string]bool)
 
 	// Add all plugin project IDs
 	for _, plugin := range m.Plugins {
 		if projectId := extractProjectId(&plugin); projectId != "" {
-			projectIds[projectId] = true
+			projectIds[projectKey(dependencySourceType(&plugin), projectId)] = true
 		}
 	}
 
 	// Add all mod project IDs
 	for _, mod := range m.Mods {
 		if projectId := extractProjectId(&mod); projectId != "" {
-			projectIds[projectId] = true
+			projectIds[projectKey(dependencySourceType(&mod), projectId)] = true
 		}
 	}
 
@@ -148,15 +204,11 @@ func extractProjectId(dep *manifest.Dependency) string {
 			continue
 		}
 
-		sourceType := strings.TrimPrefix(sourceKey, "source.")
-		switch sourceType {
-		case "modrinth", "hangar":
-			if meta, ok := sourceMeta.(map[string]any); ok {
-				for key, value := range meta {
-					if strings.EqualFold(key, "projectId") || strings.EqualFold(key, "projectSlug") {
-						if projectId, ok := value.(string); ok {
-							return projectId
-						}
+		if meta, ok := sourceMeta.(map[string]any); ok {
+			for key, value := range meta {
+				if strings.EqualFold(key, "projectId") || strings.EqualFold(key, "projectSlug") {
+					if projectId, ok := value.(string); ok {
+						return projectId
 					}
 				}
 			}
@@ -165,289 +217,124 @@ func extractProjectId(dep *manifest.Dependency) string {
 	return ""
 }
 
-func processDependencies(deps []*manifest.Dependency, depType string, m *manifest.Manifest, rootDir string, ftpClient *ftp.ServerConn, cache, newCache map[string]string, manifestProjectIds map[string]bool) error {
-	for _, dep := range deps {
-		var sourceFound bool
-		for sourceKey, sourceMeta := range dep.Metadata {
-			if !strings.HasPrefix(sourceKey, "source.") {
-				continue
-			}
-			sourceFound = true
-
-			sourceType := strings.TrimPrefix(sourceKey, "source.")
-			var err error
-			switch sourceType {
-			case "modrinth":
-				err = processModrinthDependency(dep, sourceMeta, depType, m, rootDir, ftpClient, cache, newCache, manifestProjectIds)
-			case "hangar":
-				err = processHangarDependency(dep, sourceMeta, depType, m, rootDir, ftpClient, cache, newCache, manifestProjectIds)
-			default:
-				log.Warn(fmt.Sprintf("Unknown dependency source: %s", sourceType))
-			}
+// placeDependency downloads and places the files of a resolved dependency
+func placeDependency(resolved *resolvedDependency, m *manifest.Manifest, rootDir string, ftpClient *ftp.ServerConn, cache, newCache map[string]string, lock *manifest.Lock) error {
+	dep := resolved.dep
 
-			if err != nil {
-				return err
-			}
-			// Found and processed a source, so we can break from this inner loop.
-			break
+	// Main dependency
+	dest := filepath.Join(resolved.depType, resolved.artifacts[0].Dest)
+	if err := downloadAndPlace(dep, dest, rootDir, ftpClient, cache, newCache); err != nil {
+		return err
+	}
+	lockDependency(lock, m, resolved.depType, resolved.sourceType, dep, filepath.Join(dest, dep.CanonicalFileName()))
+
+	// Additional files (e.g. Typewriter extensions)
+	for _, artifact := range resolved.artifacts[1:] {
+		artifactDep := &manifest.Dependency{
+			ProjectId:       resolved.project.ID,
+			Version:         resolved.version.Number,
+			FileName:        artifact.FileName,
+			FileHash:        artifact.FileHash,
+			DownloadUrl:     artifact.DownloadUrl,
+			DownloadHeaders: artifact.DownloadHeaders,
+			SaveAs:          artifact.FileName,
 		}
-		if !sourceFound {
-			log.Warn(fmt.Sprintf("No source found for dependency with SaveAs: %s", dep.SaveAs))
+		artifactDest := filepath.Join(resolved.depType, artifact.Dest)
+		if err := downloadAndPlace(artifactDep, artifactDest, rootDir, ftpClient, cache, newCache); err != nil {
+			return err
 		}
+		lockDependency(lock, m, resolved.depType, resolved.sourceType, artifactDep, filepath.Join(artifactDest, artifactDep.CanonicalFileName()))
 	}
 	return nil
 }
 
-func processModrinthDependency(dep *manifest.Dependency, meta any, depType string, m *manifest.Manifest, rootDir string, ftpClient *ftp.ServerConn, cache, newCache map[string]string, manifestProjectIds map[string]bool) error {
-	modrinthMeta, ok := meta.(map[string]any)
-	if !ok {
-		return fmt.Errorf("invalid modrinth metadata format for %s", dep.SaveAs)
-	}
+func downloadAndPlace(dep *manifest.Dependency, dest string, rootDir string, ftpClient *ftp.ServerConn, cache, newCache map[string]string) error {
+	finalFileName := dep.CanonicalFileName()
+	finalPath := filepath.Join(dest, finalFileName)
+	tmpPath := filepath.Join(os.TempDir(), dep.FileName)
 
-	var projectId string
-	var projectIdFound bool
-	for key, value := range modrinthMeta {
-		if strings.EqualFold(key, "projectId") {
-			projectId, projectIdFound = value.(string)
-			break
+	// Files without a known hash (e.g. a local build) can change without their name changing,
+	// so they are fetched every time and recorded in the cache and the lock by the hash of their contents
+	fetched := false
+	if dep.FileHash == "" {
+		if err := fetchFile(dep, finalPath, tmpPath); err != nil {
+			return err
 		}
-	}
-
-	if !projectIdFound {
-		return fmt.Errorf("projectId not found or not a string in modrinth metadata for %s", dep.SaveAs)
-	}
-
-	log.Task(fmt.Sprintf("Processing %s: %s", depType, projectId))
-
-	project, err := api.GetProject(projectId)
-	if err != nil {
-		return err
-	}
-
-	var versions []api.ModrinthVersion
-	if dep.DownloadIncompatible {
-		versions, err = api.GetAllVersionsFor(project)
-	} else {
-		versions, err = api.GetVersionsFor(project, m.Server)
-	}
-
-	if err != nil || len(versions) == 0 {
-		log.Warn(fmt.Sprintf("No compatible versions found for %s", dep.SaveAs))
-		return nil // Continue with next dependency
-	}
+		defer os.Remove(tmpPath)
 
-	version := api.ResolveVersion(versions, dep.WantedVersion)
-	if version == nil {
-		log.Warn(fmt.Sprintf("Wanted version '%s' not found for %s", dep.WantedVersion, dep.SaveAs))
-		return nil // Continue with next dependency
-	}
-
-	// Main dependency
-	primaryFile := getPrimaryFile(version.Files)
-	if primaryFile == nil {
-		log.Warn(fmt.Sprintf("No primary file found for %s", project.Title))
-		return nil // Continue with next dependency
-	}
-
-	dep.ProjectId = project.ID
-	dep.Version = version.VersionNumber
-	dep.FileName = primaryFile.Filename
-	dep.FileHash = primaryFile.Hashes["sha512"]
-	dep.DownloadUrl = primaryFile.URL
-
-	// Add the resolved project ID to the manifest map to prevent duplicate downloads
-	manifestProjectIds[project.ID] = true
-
-	if err = downloadAndPlace(dep, depType, rootDir, ftpClient, cache, newCache); err != nil {
-		return err
-	}
-
-	// Typewriter extensions
-	if typewriterMeta, ok := dep.Metadata["plugin.typewriter"]; ok {
-		extensions, ok := typewriterMeta.(map[string]interface{})["extensions"].([]interface{})
-		if !ok {
-			log.Warn("Invalid 'extensions' format in plugin.typewriter metadata")
-			return nil
-		}
-		for _, ext := range extensions {
-			extName, ok := ext.(string)
-			if !ok {
-				log.Warn("Invalid extension name in plugin.typewriter metadata")
-				continue
-			}
-			extFile := findFileByName(version.Files, extName+".jar")
-			if extFile == nil {
-				log.Warn(fmt.Sprintf("Extension '%s' not found for Typewriter", extName))
-				continue
-			}
-			extDep := &manifest.Dependency{
-				ProjectId:   project.ID,
-				Version:     version.VersionNumber,
-				FileName:    extFile.Filename,
-				FileHash:    extFile.Hashes["sha512"],
-				DownloadUrl: extFile.URL,
-				SaveAs:      extFile.Filename,
-			}
-			err := downloadAndPlace(extDep, filepath.Join(depType, "Typewriter", "extensions"), rootDir, ftpClient, cache, newCache)
-			if err != nil {
-				return err
-			}
+		fileHash, err := api.HashFile(tmpPath)
+		if err != nil {
+			return err
 		}
+		dep.FileHash = fileHash
+		fetched = true
 	}
 
-	// Other dependencies
-	dep.Dependencies, err = api.GetRequiredDependencies(version, m.Server, dep.DownloadIncompatible)
-	if err != nil {
-		return err
-	}
-	if len(dep.Dependencies) > 0 {
-		// Filter out dependencies that are already defined in the manifest
-		filteredDeps := make([]*manifest.Dependency, 0)
-		for _, subDep := range dep.Dependencies {
-			if subDep.ProjectId == "" {
-				// If project ID is not set, include the dependency
-				filteredDeps = append(filteredDeps, subDep)
-			} else if !manifestProjectIds[subDep.ProjectId] {
-				// Only include if not already in manifest
-				filteredDeps = append(filteredDeps, subDep)
-			} else {
-				log.Debug(fmt.Sprintf("Skipping dependency %s (project ID: %s) as it's already defined in manifest", subDep.SaveAs, subDep.ProjectId))
-			}
-		}
+	cacheKey := fmt.Sprintf("%s:%s:%s", dep.ProjectId, finalFileName, strings.ToLower(dep.FileHash))
+	newCache[cacheKey] = finalPath
 
-		if len(filteredDeps) > 0 {
-			log.Task(fmt.Sprintf("Processing required dependencies for %s", project.Title))
-			err := processDependencies(filteredDeps, depType, m, rootDir, ftpClient, cache, newCache, manifestProjectIds)
-			if err != nil {
-				return err
-			}
+	// Check cache
+	if oldFile, ok := cache[cacheKey]; ok && oldFile == finalPath {
+		log.Debug(fmt.Sprintf("File %s is already up to date", finalPath))
+		return nil
+	}
+	// Remove old file
+	prefix := fmt.Sprintf("%s:%s:", dep.ProjectId, finalFileName)
+	for oldKey, oldFile := range cache {
+		if strings.HasPrefix(oldKey, prefix) && oldFile != finalPath {
+			log.Task(fmt.Sprintf("Purging old %s", filepath.Base(oldFile)))
+			removeFile(oldFile, ftpClient)
 		}
 	}
-	return nil
-}
 
-func processHangarDependency(dep *manifest.Dependency, meta any, depType string, m *manifest.Manifest, rootDir string, ftpClient *ftp.ServerConn, cache, newCache map[string]string, manifestProjectIds map[string]bool) error {
-	hangarMeta, ok := meta.(map[string]any)
-	if !ok {
-		return fmt.Errorf("invalid hangar metadata format for %s", dep.SaveAs)
-	}
-
-	var projectSlug string
-	var projectSlugFound bool
-	for key, value := range hangarMeta {
-		if strings.EqualFold(key, "projectId") || strings.EqualFold(key, "projectSlug") {
-			projectSlug, projectSlugFound = value.(string)
-			break
+	if !fetched {
+		if err := fetchFile(dep, finalPath, tmpPath); err != nil {
+			return err
 		}
-	}
-
-	if !projectSlugFound {
-		return fmt.Errorf("projectSlug not found or not a string in hangar manifest metadata for %s", dep.SaveAs)
-	}
+		defer os.Remove(tmpPath)
 
-	log.Task(fmt.Sprintf("Processing %s: %s", depType, projectSlug))
-
-	project, err := api.GetHangarProject(projectSlug)
-	if err != nil {
-		return err
+		if err := api.VerifyFile(tmpPath, dep.FileHash); err != nil {
+			return err
+		}
 	}
 
-	var versions []api.HangarVersion
-	if dep.DownloadIncompatible {
-		versions, err = api.GetAllHangarVersionsFor(project)
-	} else {
-		versions, err = api.GetHangarVersionsFor(project, m.Server)
-	}
+	return placeFile(tmpPath, finalPath, rootDir, ftpClient)
+}
 
-	if err != nil || len(versions) == 0 {
-		log.Warn(fmt.Sprintf("No compatible versions found for %s", dep.SaveAs))
-		return nil // Continue with next dependency
+// fetchFile downloads a dependency to a temporary path, or copies it if it's a local file
+func fetchFile(dep *manifest.Dependency, finalPath, tmpPath string) error {
+	if localPath, ok := strings.CutPrefix(dep.DownloadUrl, "file://"); ok {
+		log.Task(fmt.Sprintf("Copying %s to %s", localPath, finalPath))
+		return copyLocalFile(localPath, tmpPath)
 	}
 
-	version := api.ResolveHangarVersion(versions, dep.WantedVersion)
-	if version == nil {
-		log.Warn(fmt.Sprintf("Wanted version '%s' not found for %s", dep.WantedVersion, dep.SaveAs))
-		return nil // Continue with next dependency
-	}
+	log.Task(fmt.Sprintf("Downloading %s to %s", dep.FileName, finalPath))
+	return api.DownloadFileWithHeaders(dep.DownloadUrl, tmpPath, dep.DownloadHeaders)
+}
 
-	// Get download URL and filename
-	downloadUrl, filename, err := api.GetHangarDownloadUrl(project, version, m.Server)
+// copyLocalFile copies a file on the machine running the updater
+func copyLocalFile(srcPath, destPath string) error {
+	src, err := os.Open(srcPath)
 	if err != nil {
 		return err
 	}
+	defer src.Close()
 
-	dep.ProjectId = fmt.Sprintf("%d", project.ProjectID)
-	dep.Version = version.Name
-	dep.FileName = filename
-	dep.FileHash = "" // Hangar doesn't provide hashes in the API response
-	dep.DownloadUrl = downloadUrl
-
-	// Add the resolved project ID to the manifest map to prevent duplicate downloads
-	manifestProjectIds[dep.ProjectId] = true
-
-	if err = downloadAndPlace(dep, depType, rootDir, ftpClient, cache, newCache); err != nil {
-		return err
-	}
-
-	// Handle dependencies (limited support for now)
-	dep.Dependencies, err = api.GetHangarRequiredDependencies(project, version, m.Server, dep.DownloadIncompatible)
+	dest, err := os.Create(destPath)
 	if err != nil {
 		return err
 	}
-	if len(dep.Dependencies) > 0 {
-		// Filter out dependencies that are already defined in the manifest
-		filteredDeps := make([]*manifest.Dependency, 0)
-		for _, subDep := range dep.Dependencies {
-			if subDep.ProjectId == "" {
-				// If project ID is not set, include the dependency
-				filteredDeps = append(filteredDeps, subDep)
-			} else if !manifestProjectIds[subDep.ProjectId] {
-				// Only include if not already in manifest
-				filteredDeps = append(filteredDeps, subDep)
-			} else {
-				log.Debug(fmt.Sprintf("Skipping dependency %s (project ID: %s) as it's already defined in manifest", subDep.SaveAs, subDep.ProjectId))
-			}
-		}
+	defer dest.Close()
 
-		if len(filteredDeps) > 0 {
-			log.Task(fmt.Sprintf("Processing required dependencies for %s", project.Name))
-			err := processDependencies(filteredDeps, depType, m, rootDir, ftpClient, cache, newCache, manifestProjectIds)
-			if err != nil {
-				return err
-			}
-		}
-	}
-	return nil
+	_, err = io.Copy(dest, src)
+	return err
 }
 
-func downloadAndPlace(dep *manifest.Dependency, dest string, rootDir string, ftpClient *ftp.ServerConn, cache, newCache map[string]string) error {
-	finalFileName := dep.CanonicalFileName()
-	finalPath := filepath.Join(dest, finalFileName)
-	cacheKey := fmt.Sprintf("%s:%s", dep.ProjectId, finalFileName)
-	newCache[cacheKey] = finalPath
-
-	// Check cache
-	if oldFile, ok := cache[cacheKey]; ok {
-		if oldFile == finalPath {
-			log.Debug(fmt.Sprintf("File %s is already up to date", finalPath))
-			return nil
-		}
-		// Remove old file
-		log.Task(fmt.Sprintf("Purging old %s", filepath.Base(oldFile)))
-		removeFile(oldFile, ftpClient)
-	}
-
-	// Download
-	log.Task(fmt.Sprintf("Downloading %s to %s", dep.FileName, finalPath))
-	tmpPath := filepath.Join(os.TempDir(), dep.FileName)
-	if err := api.DownloadFile(dep.DownloadUrl, tmpPath); err != nil {
-		return err
-	}
-	defer os.Remove(tmpPath)
-
-	// Place file
+// placeFile moves a local file to its final path, relative to the root dir or the FTP remote path
+func placeFile(srcPath, finalPath string, rootDir string, ftpClient *ftp.ServerConn) error {
+	dest := filepath.Dir(finalPath)
 	if ftpClient != nil {
-		file, err := os.Open(tmpPath)
+		file, err := os.Open(srcPath)
 		if err != nil {
 			return err
 		}
@@ -498,26 +385,28 @@ func downloadAndPlace(dep *manifest.Dependency, dest string, rootDir string, ftp
 			}
 		}
 
-		return os.Rename(tmpPath, destPath)
+		return os.Rename(srcPath, destPath)
 	}
 }
 
-func getPrimaryFile(files []api.ModrinthFile) *api.ModrinthFile {
-	for i := range files {
-		if files[i].Primary {
-			return &files[i]
-		}
+// purgeStale removes files of a project that were placed by a previous run, but are no longer wanted
+func purgeStale(projectId string, ftpClient *ftp.ServerConn, cache, newCache map[string]string) {
+	prefix := projectId + ":"
+	newFiles := make(map[string]bool, len(newCache))
+	for _, newFile := range newCache {
+		newFiles[newFile] = true
 	}
-	return nil
-}
 
-func findFileByName(files []api.ModrinthFile, name string) *api.ModrinthFile {
-	for i := range files {
-		if strings.EqualFold(files[i].Filename, name) {
-			return &files[i]
+	for cacheKey, oldFile := range cache {
+		if !strings.HasPrefix(cacheKey, prefix) {
+			continue
 		}
+		if _, ok := newCache[cacheKey]; ok || newFiles[oldFile] {
+			continue
+		}
+		log.Task(fmt.Sprintf("Purging old %s", filepath.Base(oldFile)))
+		removeFile(oldFile, ftpClient)
 	}
-	return nil
 }
 
 func readCache(rootDir string, ftpClient *ftp.ServerConn) map[string]string {
@@ -1,14 +1,34 @@
 package manifest
 
-import "strings"
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Relations of a dependency to its dependent
+const (
+	DependencyRequired = "required"
+	DependencyOptional = "optional"
+	DependencyEmbedded = "embedded"
+)
 
 type Dependency struct {
 	// SaveAs name as it should be saved on disk, can contain "{version}" to be replaced with the wanted version
+	// and "{minecraftVersion}" to be replaced with the server's Minecraft version
 	SaveAs string `json:"saveAs"`
 
-	// Wanted version, can be "@latest" to get the latest version
+	// Wanted version, can be "@latest" to get the latest version. Some sources support other aliases (e.g. "@lastStableBuild" for Jenkins)
 	WantedVersion string `json:"version"`
 
+	// Minimum release channel ("release", "beta" or "alpha") of "@latest", defaults to the manifest's channel
+	Channel string `json:"channel"`
+
+	// Optional dependencies to install: "none" (default), "all", or a list of project IDs or slugs.
+	// Only applies to the direct dependencies of the manifest entry, not to dependencies of dependencies.
+	OptionalDependencies OptionalDependencies `json:"optionalDependencies"`
+
 	// Download even if MC version or loader doesn't match
 	DownloadIncompatible bool `json:"downloadIncompatible"`
 
@@ -18,6 +38,12 @@ type Dependency struct {
 	// Actual version downloaded from the Modrinth API
 	Version string `json:"-"`
 
+	// Exact version ID required by the dependent, empty if any version is allowed
+	VersionId string `json:"-"`
+
+	// Relation to the dependent (DependencyRequired, DependencyOptional or DependencyEmbedded), empty for manifest dependencies
+	DependencyType string `json:"-"`
+
 	// File name of the downloaded file
 	FileName string `json:"-"`
 
@@ -32,13 +58,67 @@ type Dependency struct {
 
 	// Download URL of the jar plugin/mod
 	DownloadUrl string `json:"-"`
+
+	// Additional headers to download the jar with (e.g. authorization for private repositories)
+	DownloadHeaders map[string]string `json:"-"`
 }
 
-// CanonicalFileName returns the file name as it would be saved on the filesystem
+// CanonicalFileName returns the file name as it would be saved on the filesystem.
+// Dependencies without SaveAs are saved under their original file name.
 func (d *Dependency) CanonicalFileName() string {
+	if d.SaveAs == "" {
+		return d.FileName
+	}
 	return strings.ReplaceAll(d.SaveAs, "{version}", d.Version)
 }
 
+// OptionalDependencies selects the optional dependencies of a dependency that are installed
+type OptionalDependencies struct {
+	// Install all optional dependencies
+	All bool
+
+	// Project IDs or slugs of the optional dependencies to install
+	Projects []string
+}
+
+// UnmarshalJSON reads "none", "all" or a list of project IDs or slugs
+func (o *OptionalDependencies) UnmarshalJSON(data []byte) error {
+	var mode string
+	if err := json.Unmarshal(data, &mode); err == nil {
+		switch mode {
+		case "", "none":
+			*o = OptionalDependencies{}
+		case "all":
+			*o = OptionalDependencies{All: true}
+		default:
+			return fmt.Errorf("invalid optionalDependencies '%s', expected \"none\", \"all\" or a list of project IDs or slugs", mode)
+		}
+		return nil
+	}
+
+	var projects []string
+	if err := json.Unmarshal(data, &projects); err != nil {
+		return fmt.Errorf("invalid optionalDependencies, expected \"none\", \"all\" or a list of project IDs or slugs: %w", err)
+	}
+	*o = OptionalDependencies{Projects: projects}
+	return nil
+}
+
+// Includes reports whether an optional dependency with any of the IDs or slugs is installed
+func (o OptionalDependencies) Includes(ids ...string) bool {
+	if o.All {
+		return true
+	}
+	return slices.ContainsFunc(o.Projects, func(project string) bool {
+		return slices.ContainsFunc(ids, func(id string) bool { return id != "" && strings.EqualFold(id, project) })
+	})
+}
+
+// IsNone reports whether no optional dependencies are installed
+func (o OptionalDependencies) IsNone() bool {
+	return !o.All && len(o.Projects) == 0
+}
+
 type (
 	Plugin = Dependency
 	Mod    = Dependency
@@ -0,0 +1,53 @@
+package manifest
+
+// Lock records the exact files an update resolved, so that a deployment can be reproduced
+type Lock struct {
+	// SHA-256 hash of the manifest the lock was resolved from
+	ManifestHash string `json:"manifestHash"`
+
+	// Resolved Minecraft version
+	MinecraftVersion string `json:"minecraftVersion"`
+
+	// Server jar, nil if the manifest defines no loader
+	Server *LockedServer `json:"server,omitempty"`
+
+	// Plugins and mods, including their dependencies
+	Plugins []LockedDependency `json:"plugins,omitempty"`
+	Mods    []LockedDependency `json:"mods,omitempty"`
+}
+
+// LockedDependency is a resolved file
+type LockedDependency struct {
+	// Source the file was resolved from (e.g. "modrinth", or the loader for the server jar)
+	Source string `json:"source"`
+
+	ProjectId   string `json:"projectId"`
+	Version     string `json:"version"`
+	DownloadUrl string `json:"downloadUrl"`
+	FileName    string `json:"fileName"`
+
+	// Hash published by the source, or the SHA-256 hash of the placed file if the source publishes none
+	FileHash string `json:"fileHash"`
+
+	// Path the file is placed at, relative to the root dir
+	Path string `json:"path"`
+
+	// Index of the dependency in the manifest's plugins or mods, nil for dependencies of dependencies.
+	// Download headers (e.g. authorization) are rebuilt from the source metadata of the manifest entry.
+	ManifestIndex *int `json:"manifestIndex,omitempty"`
+}
+
+// LockedServer is a resolved server jar, or the installer that produces it
+type LockedServer struct {
+	LockedDependency
+
+	// Arguments to run the installer with, nil if the jar is the server itself
+	InstallerArgs []string `json:"installerArgs,omitempty"`
+
+	// Launcher jar produced by the installer, which is placed at Path. Installers that produce run scripts
+	// instead (e.g. Forge and NeoForge) have neither a launcher nor a Path.
+	LauncherFile string `json:"launcherFile,omitempty"`
+
+	// Files produced by the installer that are only placed on the first install
+	PreservedFiles []string `json:"preservedFiles,omitempty"`
+}
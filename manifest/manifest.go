@@ -1,5 +1,7 @@
 package manifest
 
+import "strings"
+
 type FTP struct {
 	Host       string `json:"host"`
 	Port       int    `json:"port"`
@@ -9,12 +11,28 @@ type FTP struct {
 }
 
 type Manifest struct {
-	FTP     *FTP     `json:"ftp"`
-	Server  Server   `json:"server"`
+	FTP    *FTP   `json:"ftp"`
+	Server Server `json:"server"`
+
+	// Default minimum release channel of all dependencies, empty to allow any channel
+	Channel string `json:"channel"`
+
 	Plugins []Plugin `json:"plugins"`
 	Mods    []Mod    `json:"mods"`
 }
 
+// SetMinecraftVersion sets the resolved Minecraft version and fills it into the "{minecraftVersion}" placeholders
+func (m *Manifest) SetMinecraftVersion(minecraftVersion string) {
+	m.Server.MinecraftVersion = minecraftVersion
+	m.Server.LoaderFile = strings.ReplaceAll(m.Server.LoaderFile, "{minecraftVersion}", minecraftVersion)
+	for i := range m.Plugins {
+		m.Plugins[i].SaveAs = strings.ReplaceAll(m.Plugins[i].SaveAs, "{minecraftVersion}", minecraftVersion)
+	}
+	for i := range m.Mods {
+		m.Mods[i].SaveAs = strings.ReplaceAll(m.Mods[i].SaveAs, "{minecraftVersion}", minecraftVersion)
+	}
+}
+
 func (m *Manifest) HasPlugins() bool {
 	return len(m.Plugins) > 0
 }
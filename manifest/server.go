@@ -1,9 +1,20 @@
 package manifest
 
+import "strings"
+
 type Server struct {
-	Loader           string   `json:"loader"`
-	LoaderVersion    string   `json:"loaderVersion"`
-	LoaderFile       string   `json:"loaderFile"`
-	MinecraftVersion string   `json:"minecraftVersion"`
-	Supports         []string `json:"supports"`
+	Loader           string `json:"loader"`
+	LoaderVersion    string `json:"loaderVersion"`
+	LoaderFile       string `json:"loaderFile"`
+	MinecraftVersion string `json:"minecraftVersion"`
+
+	// Version of the proxy software for Velocity and Waterfall (e.g. "3.4.0-SNAPSHOT"), defaults to "@latest"
+	ProxyVersion string `json:"proxyVersion"`
+
+	Supports []string `json:"supports"`
+}
+
+// CanonicalLoaderFile returns the server jar name as it would be saved on the filesystem
+func (s *Server) CanonicalLoaderFile(loaderVersion string) string {
+	return strings.ReplaceAll(s.LoaderFile, "{loaderVersion}", loaderVersion)
 }
@@ -0,0 +1,275 @@
+package source
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// semVersion is a leniently parsed version number. Plugin authors rarely follow semantic versioning,
+// so any number of numeric components is allowed and prefixes such as "v" are skipped.
+type semVersion struct {
+	components []int
+	prerelease string
+}
+
+// parseVersion parses a version number such as "1.2.3", "v1.2.3-SNAPSHOT" or "1.2.3+build.5".
+// Build metadata is ignored, as it has no precedence.
+func parseVersion(version string) (semVersion, bool) {
+	start := strings.IndexFunc(version, unicode.IsDigit)
+	if start == -1 {
+		return semVersion{}, false
+	}
+
+	components, rest := parseComponents(version[start:])
+	rest, _, _ = strings.Cut(rest, "+")
+	return semVersion{components: components, prerelease: strings.TrimLeft(rest, "-._ ")}, true
+}
+
+// parseComponents parses the leading dot separated numbers of a version, returning the unparsed rest
+func parseComponents(version string) ([]int, string) {
+	var components []int
+	for {
+		end := strings.IndexFunc(version, func(r rune) bool { return !unicode.IsDigit(r) })
+		if end == -1 {
+			end = len(version)
+		}
+		if end == 0 {
+			return components, version
+		}
+
+		component, err := strconv.Atoi(version[:end])
+		if err != nil {
+			return components, version
+		}
+		components = append(components, component)
+		version = version[end:]
+
+		// Only continue on a dot followed by another number
+		if len(version) < 2 || version[0] != '.' || !unicode.IsDigit(rune(version[1])) {
+			return components, version
+		}
+		version = version[1:]
+	}
+}
+
+func (v semVersion) component(i int) int {
+	if i < len(v.components) {
+		return v.components[i]
+	}
+	return 0
+}
+
+// compare compares two versions by semantic versioning precedence, treating missing components as zero
+func (v semVersion) compare(other semVersion) int {
+	for i := range max(len(v.components), len(other.components)) {
+		if c := v.component(i) - other.component(i); c != 0 {
+			return c
+		}
+	}
+
+	// A pre-release has lower precedence than its release
+	switch {
+	case v.prerelease == other.prerelease:
+		return 0
+	case v.prerelease == "":
+		return 1
+	case other.prerelease == "":
+		return -1
+	}
+	return comparePrerelease(v.prerelease, other.prerelease)
+}
+
+// comparePrerelease compares dot separated pre-release identifiers. Numeric identifiers are compared
+// numerically and have lower precedence than alphanumeric ones.
+func comparePrerelease(a, b string) int {
+	aIds, bIds := strings.Split(a, "."), strings.Split(b, ".")
+	for i := range min(len(aIds), len(bIds)) {
+		aNum, aErr := strconv.Atoi(aIds[i])
+		bNum, bErr := strconv.Atoi(bIds[i])
+		var c int
+		switch {
+		case aErr == nil && bErr == nil:
+			c = aNum - bNum
+		case aErr == nil:
+			c = -1
+		case bErr == nil:
+			c = 1
+		default:
+			c = strings.Compare(aIds[i], bIds[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return len(aIds) - len(bIds)
+}
+
+// bump returns the lowest version above all versions starting with the first n+1 components,
+// e.g. bumping 1.2.3 at 1 gives 1.3.0-0. The "0" pre-release excludes pre-releases of the bumped version.
+func (v semVersion) bump(n int) semVersion {
+	components := make([]int, n+1)
+	copy(components, v.components)
+	components[n]++
+	return semVersion{components: components, prerelease: "0"}
+}
+
+// comparator compares a version against a bound with an operator ("=", "<", "<=", ">" or ">=")
+type comparator struct {
+	op    string
+	bound semVersion
+}
+
+func (c comparator) matches(v semVersion) bool {
+	cmp := v.compare(c.bound)
+	switch c.op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	default:
+		return cmp == 0
+	}
+}
+
+// constraint is a version constraint, satisfied when all comparators of any of its ranges match
+type constraint [][]comparator
+
+// parseConstraint parses a version constraint such as "^5.4", "~2.1.0", ">=1.3 <2", "5.x" or "1.2 || ^2".
+// Ranges are separated by "||", the comparators of a range by spaces.
+func parseConstraint(expression string) (constraint, error) {
+	var result constraint
+	for _, rangeExpression := range strings.Split(expression, "||") {
+		fields := strings.Fields(rangeExpression)
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("empty range in version constraint '%s'", expression)
+		}
+
+		var comparators []comparator
+		for i := 0; i < len(fields); i++ {
+			term := fields[i]
+
+			// Allow a space between the operator and the version, e.g. ">= 1.3"
+			if strings.Trim(term, "<>=^~") == "" && i+1 < len(fields) {
+				i++
+				term += fields[i]
+			}
+
+			termComparators, err := parseTerm(term)
+			if err != nil {
+				return nil, fmt.Errorf("invalid version constraint '%s': %w", expression, err)
+			}
+			comparators = append(comparators, termComparators...)
+		}
+		result = append(result, comparators)
+	}
+	return result, nil
+}
+
+// parseTerm parses an operator followed by a possibly partial version (e.g. "^5.4" or "5.x")
+// into the comparators it stands for
+func parseTerm(term string) ([]comparator, error) {
+	op := term[:len(term)-len(strings.TrimLeft(term, "<>=^~"))]
+	if !slices.Contains([]string{"", "=", "<", "<=", ">", ">=", "^", "~"}, op) {
+		return nil, fmt.Errorf("unknown operator '%s'", op)
+	}
+
+	bound, specified, err := parsePartialVersion(strings.TrimPrefix(term[len(op):], "v"))
+	if err != nil {
+		return nil, err
+	}
+
+	// Any version
+	if specified == 0 {
+		if op == "<" || op == ">" {
+			return nil, fmt.Errorf("'%s' matches no version", term)
+		}
+		return nil, nil
+	}
+
+	partial := specified < len(bound.components) || bound.prerelease == "" && specified < 3
+	switch op {
+	case "^":
+		// Allow changes that don't modify the left-most non-zero component
+		n := slices.IndexFunc(bound.components[:specified], func(c int) bool { return c != 0 })
+		if n == -1 {
+			n = specified - 1
+		}
+		return []comparator{{">=", bound}, {"<", bound.bump(n)}}, nil
+	case "~":
+		// Allow patch level changes, or minor level changes if only the major version is specified
+		return []comparator{{">=", bound}, {"<", bound.bump(min(1, specified-1))}}, nil
+	case "", "=":
+		if partial {
+			return []comparator{{">=", bound}, {"<", bound.bump(specified - 1)}}, nil
+		}
+		return []comparator{{"=", bound}}, nil
+	case ">":
+		if partial {
+			return []comparator{{">=", bound.bump(specified - 1)}}, nil
+		}
+	case "<":
+		if partial {
+			bound.prerelease = "0"
+		}
+	case "<=":
+		if partial {
+			return []comparator{{"<", bound.bump(specified - 1)}}, nil
+		}
+	}
+	return []comparator{{op, bound}}, nil
+}
+
+// parsePartialVersion parses a version whose trailing components may be missing or wildcards ("x", "X" or "*"),
+// returning the version with wildcards as zeros and the number of specified components
+func parsePartialVersion(version string) (semVersion, int, error) {
+	if version == "" {
+		return semVersion{}, 0, fmt.Errorf("missing version")
+	}
+
+	number, _, _ := strings.Cut(version, "+")
+	number, prerelease, _ := strings.Cut(number, "-")
+
+	var result semVersion
+	specified := 0
+	wildcard := false
+	for _, part := range strings.Split(number, ".") {
+		if part == "x" || part == "X" || part == "*" {
+			wildcard = true
+			result.components = append(result.components, 0)
+			continue
+		}
+
+		component, err := strconv.Atoi(part)
+		if err != nil || wildcard {
+			return semVersion{}, 0, fmt.Errorf("invalid version '%s'", version)
+		}
+		result.components = append(result.components, component)
+		specified++
+	}
+
+	if prerelease != "" {
+		if wildcard {
+			return semVersion{}, 0, fmt.Errorf("invalid version '%s'", version)
+		}
+		result.prerelease = prerelease
+	}
+	return result, specified, nil
+}
+
+func (c constraint) matches(v semVersion) bool {
+	return slices.ContainsFunc(c, func(comparators []comparator) bool {
+		for _, comparator := range comparators {
+			if !comparator.matches(v) {
+				return false
+			}
+		}
+		return true
+	})
+}
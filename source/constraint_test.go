@@ -0,0 +1,131 @@
+package source
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		version    string
+		components []int
+		prerelease string
+		ok         bool
+	}{
+		{version: "1.2.3", components: []int{1, 2, 3}, ok: true},
+		{version: "v1.2.3-SNAPSHOT", components: []int{1, 2, 3}, prerelease: "SNAPSHOT", ok: true},
+		{version: "1.2.3+build.5", components: []int{1, 2, 3}, ok: true},
+		{version: "1.2.3-beta.1+build.5", components: []int{1, 2, 3}, prerelease: "beta.1", ok: true},
+		{version: "5.4", components: []int{5, 4}, ok: true},
+		{version: "release", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			got, ok := parseVersion(tt.version)
+			if ok != tt.ok {
+				t.Fatalf("got ok %v, want %v", ok, tt.ok)
+			}
+			if !slices.Equal(got.components, tt.components) || got.prerelease != tt.prerelease {
+				t.Errorf("got %v-%q, want %v-%q", got.components, got.prerelease, tt.components, tt.prerelease)
+			}
+		})
+	}
+}
+
+func TestParseConstraint(t *testing.T) {
+	tests := []struct {
+		constraint string
+		matching   []string
+		other      []string
+	}{
+		{constraint: "^5.4", matching: []string{"5.4", "5.4.0", "5.9.1"}, other: []string{"5.3.9", "6.0.0", "6.0.0-beta"}},
+		{constraint: "^0.2.3", matching: []string{"0.2.3", "0.2.9"}, other: []string{"0.3.0", "0.2.2"}},
+		{constraint: "~2.1.0", matching: []string{"2.1.0", "2.1.5"}, other: []string{"2.0.9", "2.2.0"}},
+		{constraint: ">=1.3 <2", matching: []string{"1.3", "1.9.9"}, other: []string{"1.2", "2.0", "2.0.0-rc1"}},
+		{constraint: ">= 1.3", matching: []string{"1.3", "4.0"}, other: []string{"1.2.9"}},
+		{constraint: "5.x", matching: []string{"5.0", "5.12.3"}, other: []string{"4.9", "6.0"}},
+		{constraint: "1.2", matching: []string{"1.2", "1.2.10"}, other: []string{"1.3", "1.1.9"}},
+		{constraint: "1.2.3", matching: []string{"1.2.3", "v1.2.3+build.5"}, other: []string{"1.2.4", "1.2.3-SNAPSHOT"}},
+		{constraint: "1.2 || ^2", matching: []string{"1.2.5", "2.5"}, other: []string{"1.3", "3.0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			c, err := parseConstraint(tt.constraint)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, version := range tt.matching {
+				if v, _ := parseVersion(version); !c.matches(v) {
+					t.Errorf("%s should match %s", tt.constraint, version)
+				}
+			}
+			for _, version := range tt.other {
+				if v, _ := parseVersion(version); c.matches(v) {
+					t.Errorf("%s shouldn't match %s", tt.constraint, version)
+				}
+			}
+		})
+	}
+}
+
+func TestParseConstraintErrors(t *testing.T) {
+	for _, expression := range []string{"", "1.2 ||", "!1.2", "1.x.3", "1.x-beta", "<x", "latest"} {
+		if _, err := parseConstraint(expression); err == nil {
+			t.Errorf("expected an error for '%s'", expression)
+		}
+	}
+}
+
+func TestSelectVersion(t *testing.T) {
+	versions := func(numbers ...string) []*Version {
+		var result []*Version
+		for _, number := range numbers {
+			channel := ChannelRelease
+			if v, _ := parseVersion(number); v.prerelease != "" {
+				channel = ChannelBeta
+			}
+			result = append(result, &Version{ID: number, Number: number, Channel: channel})
+		}
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		versions []*Version
+		wanted   string
+		channel  string
+		want     string
+	}{
+		{name: "latest", versions: versions("2.0.0-beta", "1.9.0"), wanted: "@latest", want: "2.0.0-beta"},
+		{name: "latest release", versions: versions("2.0.0-beta", "1.9.0"), wanted: "@latest", channel: ChannelRelease, want: "1.9.0"},
+		{name: "latest release ignoring case", versions: versions("2.0.0-beta", "1.9.0"), wanted: "@latest", channel: "Release", want: "1.9.0"},
+		{name: "caret", versions: versions("6.0.0", "5.9.1", "5.4.0", "5.3.0"), wanted: "^5.4", want: "5.9.1"},
+		{name: "caret release", versions: versions("5.10.0-beta", "5.9.1"), wanted: "^5.4", channel: ChannelRelease, want: "5.9.1"},
+		{name: "tilde", versions: versions("2.2.0", "2.1.7", "2.1.0"), wanted: "~2.1.0", want: "2.1.7"},
+		{name: "range", versions: versions("2.0", "1.9.9", "1.3"), wanted: ">=1.3 <2", want: "1.9.9"},
+		{name: "wildcard", versions: versions("6.0", "5.12.3", "5.2"), wanted: "5.x", want: "5.12.3"},
+		{name: "unsorted", versions: versions("1.2.3", "1.2.10", "1.2.9"), wanted: "1.2.x", want: "1.2.10"},
+		{name: "exact", versions: versions("1.2.10", "1.2", "1.1"), wanted: "1.2", want: "1.2"},
+		{name: "partial without exact match", versions: versions("1.3.0", "1.2.10", "1.2.3", "1.1"), wanted: "1.2", want: "1.2.10"},
+		{name: "exact pre-release below channel", versions: versions("v1.2.3-SNAPSHOT", "1.2.2"), wanted: "v1.2.3-SNAPSHOT", channel: ChannelRelease, want: "v1.2.3-SNAPSHOT"},
+		{name: "build metadata", versions: versions("1.2.4", "1.2.3+build.5"), wanted: "1.2.3", want: "1.2.3+build.5"},
+		{name: "alias", versions: []*Version{{Number: "42", Aliases: []string{"@lastStableBuild"}}}, wanted: "@lastStableBuild", want: "42"},
+		{name: "no match", versions: versions("2.0", "1.0"), wanted: "^3", want: ""},
+		{name: "invalid constraint", versions: versions("2.0", "1.0"), wanted: "latest", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SelectVersion(tt.versions, tt.wanted, tt.channel)
+			var number string
+			if got != nil {
+				number = got.Number
+			}
+			if number != tt.want {
+				t.Errorf("got %q, want %q", number, tt.want)
+			}
+		})
+	}
+}
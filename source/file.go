@@ -0,0 +1,53 @@
+package source
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/SKevo18/server_updater/manifest"
+)
+
+type fileSource struct{}
+
+func init() {
+	Register("file", fileSource{})
+}
+
+// ResolveProject reads the "path" of a file on the machine running the updater (relative to the working directory),
+// which can contain "{version}" to be replaced with the wanted version, and an optional expected "sha256" or "sha512" hash
+func (fileSource) ResolveProject(dep *manifest.Dependency, meta map[string]any) (*Project, error) {
+	path, ok := stringMeta(meta, "path")
+	if !ok {
+		return nil, fmt.Errorf("path not found or not a string in file metadata")
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := newStaticProject(absPath, dep, meta)
+	if err != nil {
+		return nil, err
+	}
+	return &Project{ID: absPath, Name: filepath.Base(absPath), Data: project}, nil
+}
+
+func (fileSource) ListVersions(project *Project, server manifest.Server, incompatible bool) ([]*Version, error) {
+	return project.Data.(*staticProject).versions(), nil
+}
+
+func (fileSource) Artifacts(dep *manifest.Dependency, project *Project, version *Version, server manifest.Server) ([]*Artifact, error) {
+	staticProject := project.Data.(*staticProject)
+	path := staticProject.resolve(version)
+
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("local file %s not found: %w", path, err)
+	}
+	return []*Artifact{{FileName: filepath.Base(path), FileHash: staticProject.fileHash, DownloadUrl: "file://" + path}}, nil
+}
+
+func (fileSource) Dependencies(project *Project, version *Version, server manifest.Server, incompatible bool) ([]*manifest.Dependency, error) {
+	return nil, nil
+}
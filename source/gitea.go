@@ -0,0 +1,88 @@
+package source
+
+import (
+	"fmt"
+
+	"github.com/SKevo18/server_updater/api"
+	"github.com/SKevo18/server_updater/manifest"
+)
+
+type giteaSource struct{}
+
+func init() {
+	Register("gitea", giteaSource{})
+}
+
+// ResolveProject reads the Gitea or Forgejo instance "url", the "repo" ("owner/repo"), optional "asset" glob pattern
+// (defaults to "*.jar"), "includePrereleases" and "token" (defaults to the GITEA_TOKEN environment variable)
+func (giteaSource) ResolveProject(dep *manifest.Dependency, meta map[string]any) (*Project, error) {
+	project, err := newGiteaProject(meta)
+	if err != nil {
+		return nil, err
+	}
+	return &Project{ID: fmt.Sprintf("%s/%s", project.baseUrl, project.repo), Name: project.repo, Data: project}, nil
+}
+
+func newGiteaProject(meta map[string]any) (*releaseProject, error) {
+	project, err := newReleaseProject(meta, "repo", "GITEA_TOKEN")
+	if err != nil {
+		return nil, fmt.Errorf("invalid gitea metadata: %w", err)
+	}
+
+	var ok bool
+	if project.baseUrl, ok = stringMeta(meta, "url"); !ok {
+		return nil, fmt.Errorf("url not found or not a string in gitea metadata")
+	}
+	return project, nil
+}
+
+// ListVersions lists releases that have a matching asset. Gitea releases have no compatibility information.
+func (giteaSource) ListVersions(project *Project, server manifest.Server, incompatible bool) ([]*Version, error) {
+	releaseProject := project.Data.(*releaseProject)
+
+	releases, err := api.GetGiteaReleases(releaseProject.baseUrl, releaseProject.repo, releaseProject.token)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]*Version, 0, len(releases))
+	for i := range releases {
+		release := &releases[i]
+		if release.Draft || (release.Prerelease && !releaseProject.includePrereleases) {
+			continue
+		}
+		if findGiteaAsset(releaseProject, release.Assets) == nil {
+			continue
+		}
+		versions = append(versions, &Version{ID: fmt.Sprintf("%d", release.ID), Number: release.TagName, Channel: releaseChannel(release.Prerelease), Data: release})
+	}
+	return versions, nil
+}
+
+func (giteaSource) Artifacts(dep *manifest.Dependency, project *Project, version *Version, server manifest.Server) ([]*Artifact, error) {
+	releaseProject := project.Data.(*releaseProject)
+	asset := findGiteaAsset(releaseProject, version.Data.(*api.GiteaRelease).Assets)
+
+	return []*Artifact{{FileName: asset.Name, DownloadUrl: asset.BrowserDownloadURL, DownloadHeaders: releaseProject.instanceHeaders(asset.BrowserDownloadURL, api.GiteaHeaders(releaseProject.token))}}, nil
+}
+
+func (giteaSource) DownloadHeaders(meta map[string]any, downloadUrl string) (map[string]string, error) {
+	project, err := newGiteaProject(meta)
+	if err != nil {
+		return nil, err
+	}
+	return project.instanceHeaders(downloadUrl, api.GiteaHeaders(project.token)), nil
+}
+
+func (giteaSource) Dependencies(project *Project, version *Version, server manifest.Server, incompatible bool) ([]*manifest.Dependency, error) {
+	return nil, nil
+}
+
+func findGiteaAsset(project *releaseProject, assets []api.GiteaAsset) *api.GiteaAsset {
+	for i := range assets {
+		if project.matchesAsset(assets[i].Name) {
+			return &assets[i]
+		}
+	}
+	return nil
+}
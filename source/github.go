@@ -0,0 +1,93 @@
+package source
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SKevo18/server_updater/api"
+	"github.com/SKevo18/server_updater/manifest"
+)
+
+type githubSource struct{}
+
+func init() {
+	Register("github", githubSource{})
+}
+
+// ResolveProject reads the "repo" ("owner/repo"), optional "asset" glob pattern (defaults to "*.jar"),
+// "includePrereleases" and "token" (defaults to the GITHUB_TOKEN environment variable)
+func (githubSource) ResolveProject(dep *manifest.Dependency, meta map[string]any) (*Project, error) {
+	project, err := newReleaseProject(meta, "repo", "GITHUB_TOKEN")
+	if err != nil {
+		return nil, fmt.Errorf("invalid github metadata: %w", err)
+	}
+	return &Project{ID: project.repo, Name: project.repo, Data: project}, nil
+}
+
+// ListVersions lists releases that have a matching asset. GitHub releases have no compatibility information.
+func (githubSource) ListVersions(project *Project, server manifest.Server, incompatible bool) ([]*Version, error) {
+	releaseProject := project.Data.(*releaseProject)
+
+	releases, err := api.GetGitHubReleases(releaseProject.repo, releaseProject.token)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]*Version, 0, len(releases))
+	for i := range releases {
+		release := &releases[i]
+		if release.Draft || (release.Prerelease && !releaseProject.includePrereleases) {
+			continue
+		}
+		if findGitHubAsset(releaseProject, release.Assets) == nil {
+			continue
+		}
+		versions = append(versions, &Version{ID: fmt.Sprintf("%d", release.ID), Number: release.TagName, Channel: releaseChannel(release.Prerelease), Data: release})
+	}
+	return versions, nil
+}
+
+func (githubSource) Artifacts(dep *manifest.Dependency, project *Project, version *Version, server manifest.Server) ([]*Artifact, error) {
+	releaseProject := project.Data.(*releaseProject)
+	asset := findGitHubAsset(releaseProject, version.Data.(*api.GitHubRelease).Assets)
+
+	// Assets of private repositories can only be downloaded through the API
+	if releaseProject.token != "" {
+		return []*Artifact{{FileName: asset.Name, FileHash: asset.Hash(), DownloadUrl: asset.URL, DownloadHeaders: githubAssetHeaders(releaseProject)}}, nil
+	}
+	return []*Artifact{{FileName: asset.Name, FileHash: asset.Hash(), DownloadUrl: asset.BrowserDownloadURL}}, nil
+}
+
+func (githubSource) DownloadHeaders(meta map[string]any, downloadUrl string) (map[string]string, error) {
+	project, err := newReleaseProject(meta, "repo", "GITHUB_TOKEN")
+	if err != nil {
+		return nil, fmt.Errorf("invalid github metadata: %w", err)
+	}
+	if !strings.HasPrefix(downloadUrl, api.GitHubApiUrl+"/") {
+		return nil, nil
+	}
+	return githubAssetHeaders(project), nil
+}
+
+// githubAssetHeaders returns the headers to download an asset through the API, which returns its metadata otherwise
+func githubAssetHeaders(project *releaseProject) map[string]string {
+	headers := api.GitHubHeaders(project.token)
+	if headers == nil {
+		headers = make(map[string]string)
+	}
+	headers["Accept"] = "application/octet-stream"
+	return headers
+}
+
+func (githubSource) Dependencies(project *Project, version *Version, server manifest.Server, incompatible bool) ([]*manifest.Dependency, error) {
+	return nil, nil
+}
+
+func findGitHubAsset(project *releaseProject, assets []api.GitHubAsset) *api.GitHubAsset {
+	for i := range assets {
+		if project.matchesAsset(assets[i].Name) {
+			return &assets[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package source
+
+import (
+	"fmt"
+
+	"github.com/SKevo18/server_updater/api"
+	"github.com/SKevo18/server_updater/manifest"
+)
+
+type gitlabSource struct{}
+
+func init() {
+	Register("gitlab", gitlabSource{})
+}
+
+// ResolveProject reads the instance "url" (defaults to "https://gitlab.com"), the "project" path ("group/project"),
+// optional "asset" glob pattern (defaults to "*.jar"), "includePrereleases" (upcoming releases)
+// and "token" (defaults to the GITLAB_TOKEN environment variable)
+func (gitlabSource) ResolveProject(dep *manifest.Dependency, meta map[string]any) (*Project, error) {
+	project, err := newGitLabProject(meta)
+	if err != nil {
+		return nil, err
+	}
+	return &Project{ID: fmt.Sprintf("%s/%s", project.baseUrl, project.repo), Name: project.repo, Data: project}, nil
+}
+
+func newGitLabProject(meta map[string]any) (*releaseProject, error) {
+	project, err := newReleaseProject(meta, "project", "GITLAB_TOKEN")
+	if err != nil {
+		return nil, fmt.Errorf("invalid gitlab metadata: %w", err)
+	}
+
+	project.baseUrl = "https://gitlab.com"
+	if baseUrl, ok := stringMeta(meta, "url"); ok {
+		project.baseUrl = baseUrl
+	}
+	return project, nil
+}
+
+// ListVersions lists releases that have a matching asset link. GitLab releases have no compatibility information.
+func (gitlabSource) ListVersions(project *Project, server manifest.Server, incompatible bool) ([]*Version, error) {
+	releaseProject := project.Data.(*releaseProject)
+
+	releases, err := api.GetGitLabReleases(releaseProject.baseUrl, releaseProject.repo, releaseProject.token)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]*Version, 0, len(releases))
+	for i := range releases {
+		release := &releases[i]
+		if release.UpcomingRelease && !releaseProject.includePrereleases {
+			continue
+		}
+		if findGitLabAsset(releaseProject, release.Assets.Links) == nil {
+			continue
+		}
+		versions = append(versions, &Version{ID: release.TagName, Number: release.TagName, Channel: releaseChannel(release.UpcomingRelease), Data: release})
+	}
+	return versions, nil
+}
+
+func (gitlabSource) Artifacts(dep *manifest.Dependency, project *Project, version *Version, server manifest.Server) ([]*Artifact, error) {
+	releaseProject := project.Data.(*releaseProject)
+	link := findGitLabAsset(releaseProject, version.Data.(*api.GitLabRelease).Assets.Links)
+
+	downloadUrl := link.DirectAssetURL
+	if downloadUrl == "" {
+		downloadUrl = link.URL
+	}
+	return []*Artifact{{FileName: link.Name, DownloadUrl: downloadUrl, DownloadHeaders: releaseProject.instanceHeaders(downloadUrl, api.GitLabHeaders(releaseProject.token))}}, nil
+}
+
+func (gitlabSource) DownloadHeaders(meta map[string]any, downloadUrl string) (map[string]string, error) {
+	project, err := newGitLabProject(meta)
+	if err != nil {
+		return nil, err
+	}
+	return project.instanceHeaders(downloadUrl, api.GitLabHeaders(project.token)), nil
+}
+
+func (gitlabSource) Dependencies(project *Project, version *Version, server manifest.Server, incompatible bool) ([]*manifest.Dependency, error) {
+	return nil, nil
+}
+
+func findGitLabAsset(project *releaseProject, links []api.GitLabReleaseLink) *api.GitLabReleaseLink {
+	for i := range links {
+		if project.matchesAsset(links[i].Name) {
+			return &links[i]
+		}
+	}
+	return nil
+}
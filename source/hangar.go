@@ -0,0 +1,117 @@
+package source
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/SKevo18/server_updater/api"
+	"github.com/SKevo18/server_updater/manifest"
+	log "github.com/gwillem/go-simplelog"
+)
+
+type hangarSource struct{}
+
+type hangarProject struct {
+	*api.HangarProject
+	wantedVersion string
+	channel       string
+
+	// Name of the Hangar channel versions must be published in, empty for any channel
+	namedChannel string
+}
+
+func init() {
+	Register("hangar", hangarSource{})
+}
+
+func (hangarSource) ResolveProject(dep *manifest.Dependency, meta map[string]any) (*Project, error) {
+	projectSlug, ok := stringMeta(meta, "projectId", "projectSlug")
+	if !ok {
+		return nil, fmt.Errorf("projectSlug not found or not a string in hangar manifest metadata")
+	}
+
+	project, err := api.GetHangarProject(projectSlug)
+	if err != nil {
+		return nil, err
+	}
+	namedChannel, _ := stringMeta(meta, "channel")
+	return &Project{ID: fmt.Sprintf("%d", project.ProjectID), Name: project.Name, Slug: project.Namespace.Slug, Data: &hangarProject{project, dep.WantedVersion, dep.Channel, namedChannel}}, nil
+}
+
+func (hangarSource) ListVersions(project *Project, server manifest.Server, incompatible bool) ([]*Version, error) {
+	hangarProject := project.Data.(*hangarProject)
+
+	// Stop paging once the wanted version is found, which is the first stable enough version for "@latest"
+	found := func(v *api.HangarVersion) bool {
+		return hangarProject.inNamedChannel(v) && MatchesVersion(newHangarVersion(v), hangarProject.wantedVersion, hangarProject.channel)
+	}
+
+	var versions []api.HangarVersion
+	var err error
+	if incompatible {
+		versions, err = api.GetAllHangarVersionsFor(hangarProject.HangarProject, found)
+	} else {
+		versions, err = api.GetHangarVersionsFor(hangarProject.HangarProject, server, found)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*Version, 0, len(versions))
+	for i := range versions {
+		if hangarProject.inNamedChannel(&versions[i]) {
+			result = append(result, newHangarVersion(&versions[i]))
+		}
+	}
+	return result, nil
+}
+
+func newHangarVersion(v *api.HangarVersion) *Version {
+	return &Version{ID: v.Name, Number: v.Name, Channel: hangarChannel(v.Channel), Data: v}
+}
+
+// inNamedChannel reports whether the version is published in the wanted Hangar channel
+func (p *hangarProject) inNamedChannel(v *api.HangarVersion) bool {
+	return p.namedChannel == "" || strings.EqualFold(v.Channel.Name, p.namedChannel)
+}
+
+// hangarChannel maps a Hangar channel to a release channel. Hangar channels are named freely by
+// project owners, so unknown channels are releases unless they are flagged as unstable.
+func hangarChannel(channel api.Channel) string {
+	switch strings.ToLower(channel.Name) {
+	case "release", "stable":
+		return ChannelRelease
+	case "beta":
+		return ChannelBeta
+	case "alpha", "snapshot", "dev":
+		return ChannelAlpha
+	}
+
+	if slices.Contains(channel.Flags, "UNSTABLE") {
+		return ChannelBeta
+	}
+	return ChannelRelease
+}
+
+func (hangarSource) Artifacts(dep *manifest.Dependency, project *Project, version *Version, server manifest.Server) ([]*Artifact, error) {
+	hangarVersion := version.Data.(*api.HangarVersion)
+	download, err := api.GetHangarDownload(hangarVersion, server)
+	if err != nil {
+		return nil, err
+	}
+
+	if download.FileInfo == nil {
+		log.Debug(fmt.Sprintf("%s %s is hosted externally at %s", project.Name, hangarVersion.Name, download.ExternalURL))
+	}
+
+	return []*Artifact{{
+		FileName:    download.FileName(project.Data.(*hangarProject).HangarProject, hangarVersion),
+		FileHash:    download.Hash(),
+		DownloadUrl: download.Url(),
+	}}, nil
+}
+
+func (hangarSource) Dependencies(project *Project, version *Version, server manifest.Server, incompatible bool) ([]*manifest.Dependency, error) {
+	return api.GetHangarDependencies(project.Data.(*hangarProject).HangarProject, version.Data.(*api.HangarVersion), server, incompatible)
+}
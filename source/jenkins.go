@@ -0,0 +1,85 @@
+package source
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/SKevo18/server_updater/api"
+	"github.com/SKevo18/server_updater/manifest"
+)
+
+type jenkinsSource struct{}
+
+type jenkinsProject struct {
+	url             string
+	job             string
+	artifactPattern string
+}
+
+func init() {
+	Register("jenkins", jenkinsSource{})
+}
+
+// ResolveProject reads the Jenkins "url", the "job" (can be nested in folders, e.g. "folder/job")
+// and an optional "artifact" glob pattern (defaults to "*.jar")
+func (jenkinsSource) ResolveProject(dep *manifest.Dependency, meta map[string]any) (*Project, error) {
+	jenkinsUrl, ok := stringMeta(meta, "url")
+	if !ok {
+		return nil, fmt.Errorf("url not found or not a string in jenkins metadata")
+	}
+	job, ok := stringMeta(meta, "job")
+	if !ok {
+		return nil, fmt.Errorf("job not found or not a string in jenkins metadata")
+	}
+
+	project := &jenkinsProject{url: jenkinsUrl, job: job, artifactPattern: "*.jar"}
+	if artifactPattern, ok := stringMeta(meta, "artifact"); ok {
+		project.artifactPattern = artifactPattern
+	}
+
+	return &Project{ID: fmt.Sprintf("%s/%s", jenkinsUrl, job), Name: job, Data: project}, nil
+}
+
+// ListVersions lists successful builds with a matching artifact. The build number is the version, and the builds
+// Jenkins considers the last successful and last stable can be wanted as "@lastSuccessfulBuild" and "@lastStableBuild".
+// Jenkins builds have no compatibility information.
+func (jenkinsSource) ListVersions(project *Project, server manifest.Server, incompatible bool) ([]*Version, error) {
+	jenkinsProject := project.Data.(*jenkinsProject)
+
+	job, err := api.GetJenkinsJob(jenkinsProject.url, jenkinsProject.job)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]*Version, 0, len(job.Builds))
+	for i := range job.Builds {
+		build := &job.Builds[i]
+		if build.Result != "SUCCESS" && build.Result != "UNSTABLE" {
+			continue
+		}
+		if api.FindJenkinsArtifact(build.Artifacts, jenkinsProject.artifactPattern) == nil {
+			continue
+		}
+
+		version := &Version{ID: strconv.Itoa(build.Number), Number: strconv.Itoa(build.Number), Data: build}
+		if job.LastSuccessfulBuild != nil && job.LastSuccessfulBuild.Number == build.Number {
+			version.Aliases = append(version.Aliases, "@lastSuccessfulBuild")
+		}
+		if job.LastStableBuild != nil && job.LastStableBuild.Number == build.Number {
+			version.Aliases = append(version.Aliases, "@lastStableBuild")
+		}
+		versions = append(versions, version)
+	}
+	return versions, nil
+}
+
+func (jenkinsSource) Artifacts(dep *manifest.Dependency, project *Project, version *Version, server manifest.Server) ([]*Artifact, error) {
+	build := version.Data.(*api.JenkinsBuild)
+	artifact := api.FindJenkinsArtifact(build.Artifacts, project.Data.(*jenkinsProject).artifactPattern)
+
+	return []*Artifact{{FileName: artifact.FileName, DownloadUrl: build.ArtifactUrl(artifact)}}, nil
+}
+
+func (jenkinsSource) Dependencies(project *Project, version *Version, server manifest.Server, incompatible bool) ([]*manifest.Dependency, error) {
+	return nil, nil
+}
@@ -0,0 +1,103 @@
+package source
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SKevo18/server_updater/api"
+	"github.com/SKevo18/server_updater/manifest"
+	log "github.com/gwillem/go-simplelog"
+)
+
+type mavenSource struct{}
+
+type mavenProject struct {
+	repository string
+	groupId    string
+	artifactId string
+	classifier string
+	metadata   *api.MavenMetadata
+}
+
+func init() {
+	Register("maven", mavenSource{})
+}
+
+// ResolveProject reads the "repository" URL, "groupId", "artifactId" and an optional "classifier"
+func (mavenSource) ResolveProject(dep *manifest.Dependency, meta map[string]any) (*Project, error) {
+	project := &mavenProject{}
+
+	var ok bool
+	if project.repository, ok = stringMeta(meta, "repository"); !ok {
+		return nil, fmt.Errorf("repository not found or not a string in maven metadata")
+	}
+	if project.groupId, ok = stringMeta(meta, "groupId"); !ok {
+		return nil, fmt.Errorf("groupId not found or not a string in maven metadata")
+	}
+	if project.artifactId, ok = stringMeta(meta, "artifactId"); !ok {
+		return nil, fmt.Errorf("artifactId not found or not a string in maven metadata")
+	}
+	project.classifier, _ = stringMeta(meta, "classifier")
+
+	metadata, err := api.GetMavenMetadata(project.repository, project.groupId, project.artifactId)
+	if err != nil {
+		return nil, err
+	}
+	project.metadata = metadata
+
+	return &Project{ID: fmt.Sprintf("%s:%s", project.groupId, project.artifactId), Name: project.artifactId, Data: project}, nil
+}
+
+// ListVersions lists the versions from maven-metadata.xml, newest first. The release version can be wanted as "@release".
+// Maven artifacts have no compatibility information.
+func (mavenSource) ListVersions(project *Project, server manifest.Server, incompatible bool) ([]*Version, error) {
+	versioning := project.Data.(*mavenProject).metadata.Versioning
+
+	versions := make([]*Version, 0, len(versioning.Versions))
+	for i := len(versioning.Versions) - 1; i >= 0; i-- {
+		v := versioning.Versions[i]
+		version := &Version{ID: v, Number: v, Channel: ChannelRelease, Data: v}
+		if v == versioning.Release {
+			version.Aliases = append(version.Aliases, "@release")
+		}
+
+		if strings.HasSuffix(v, "-SNAPSHOT") {
+			version.Channel = ChannelAlpha
+		}
+		versions = append(versions, version)
+	}
+	return versions, nil
+}
+
+// Artifacts returns the jar of a version. SNAPSHOT versions are resolved to the jar of their latest timestamped build,
+// as repositories with unique snapshots have no "-SNAPSHOT" jar.
+func (mavenSource) Artifacts(dep *manifest.Dependency, project *Project, version *Version, server manifest.Server) ([]*Artifact, error) {
+	mavenProject := project.Data.(*mavenProject)
+
+	fileVersion := version.Data.(string)
+	if strings.HasSuffix(fileVersion, "-SNAPSHOT") {
+		snapshot, err := api.GetMavenSnapshotMetadata(mavenProject.repository, mavenProject.groupId, mavenProject.artifactId, fileVersion)
+		if err != nil {
+			return nil, err
+		}
+		fileVersion = snapshot.SnapshotValue(mavenProject.classifier, "jar")
+	}
+
+	fileName := fmt.Sprintf("%s-%s", mavenProject.artifactId, fileVersion)
+	if mavenProject.classifier != "" {
+		fileName += "-" + mavenProject.classifier
+	}
+	fileName += ".jar"
+
+	downloadUrl := api.MavenArtifactUrl(mavenProject.repository, mavenProject.groupId, mavenProject.artifactId, version.Data.(string), fileName)
+	fileHash := api.GetMavenChecksum(downloadUrl)
+	if fileHash == "" {
+		log.Warn(fmt.Sprintf("No checksum published for %s, it won't be verified", fileName))
+	}
+
+	return []*Artifact{{FileName: fileName, FileHash: fileHash, DownloadUrl: downloadUrl}}, nil
+}
+
+func (mavenSource) Dependencies(project *Project, version *Version, server manifest.Server, incompatible bool) ([]*manifest.Dependency, error) {
+	return nil, nil
+}
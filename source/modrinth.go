@@ -0,0 +1,151 @@
+package source
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SKevo18/server_updater/api"
+	"github.com/SKevo18/server_updater/manifest"
+	log "github.com/gwillem/go-simplelog"
+)
+
+type modrinthSource struct{}
+
+func init() {
+	Register("modrinth", modrinthSource{})
+}
+
+func (modrinthSource) ResolveProject(dep *manifest.Dependency, meta map[string]any) (*Project, error) {
+	projectId, ok := stringMeta(meta, "projectId")
+	if !ok {
+		return nil, fmt.Errorf("projectId not found or not a string in modrinth metadata")
+	}
+
+	project, err := api.GetProject(projectId)
+	if err != nil {
+		return nil, err
+	}
+	return &Project{ID: project.ID, Name: project.Title, Slug: project.Slug, Data: project}, nil
+}
+
+func (modrinthSource) ListVersions(project *Project, server manifest.Server, incompatible bool) ([]*Version, error) {
+	modrinthProject := project.Data.(*api.ModrinthProject)
+
+	var versions []api.ModrinthVersion
+	var err error
+	if incompatible {
+		versions, err = api.GetAllVersionsFor(modrinthProject)
+	} else {
+		versions, err = api.GetVersionsFor(modrinthProject, server)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*Version, len(versions))
+	for i := range versions {
+		result[i] = newModrinthVersion(&versions[i])
+	}
+	return result, nil
+}
+
+func (modrinthSource) FetchVersion(project *Project, id string) (*Version, error) {
+	version, err := api.GetVersion(id)
+	if err != nil {
+		return nil, err
+	}
+	if version.ProjectID != project.ID {
+		return nil, fmt.Errorf("version %s is not a version of %s", id, project.Name)
+	}
+	return newModrinthVersion(version), nil
+}
+
+func newModrinthVersion(version *api.ModrinthVersion) *Version {
+	return &Version{ID: version.ID, Number: version.VersionNumber, Channel: version.VersionType, Data: version}
+}
+
+func (modrinthSource) Artifacts(dep *manifest.Dependency, project *Project, version *Version, server manifest.Server) ([]*Artifact, error) {
+	modrinthVersion := version.Data.(*api.ModrinthVersion)
+
+	primaryFile := getPrimaryFile(modrinthVersion.Files)
+	if primaryFile == nil {
+		return nil, nil
+	}
+	artifacts := []*Artifact{modrinthArtifact(primaryFile, "")}
+
+	// Typewriter extensions
+	if typewriterMeta, ok := dep.Metadata["plugin.typewriter"]; ok {
+		extensions, ok := typewriterMeta.(map[string]interface{})["extensions"].([]interface{})
+		if !ok {
+			log.Warn("Invalid 'extensions' format in plugin.typewriter metadata")
+			return artifacts, nil
+		}
+		for _, ext := range extensions {
+			extName, ok := ext.(string)
+			if !ok {
+				log.Warn("Invalid extension name in plugin.typewriter metadata")
+				continue
+			}
+			extFile := findFileByName(modrinthVersion.Files, extName+".jar")
+			if extFile == nil {
+				log.Warn(fmt.Sprintf("Extension '%s' not found for Typewriter", extName))
+				continue
+			}
+			artifacts = append(artifacts, modrinthArtifact(extFile, "Typewriter/extensions"))
+		}
+	}
+
+	return artifacts, nil
+}
+
+func (modrinthSource) Dependencies(project *Project, version *Version, server manifest.Server, incompatible bool) ([]*manifest.Dependency, error) {
+	return api.GetDependencies(version.Data.(*api.ModrinthVersion), incompatible)
+}
+
+func (modrinthSource) Incompatibilities(project *Project, version *Version) ([]Incompatibility, error) {
+	var incompatibilities []Incompatibility
+	for _, dep := range version.Data.(*api.ModrinthVersion).Dependencies {
+		if dep.DependencyType != "incompatible" {
+			continue
+		}
+
+		var incompatibility Incompatibility
+		if dep.ProjectID != nil {
+			incompatibility.ProjectId = *dep.ProjectID
+		}
+		if dep.VersionID != nil {
+			incompatibility.VersionId = *dep.VersionID
+		}
+		if incompatibility != (Incompatibility{}) {
+			incompatibilities = append(incompatibilities, incompatibility)
+		}
+	}
+	return incompatibilities, nil
+}
+
+func modrinthArtifact(file *api.ModrinthFile, dest string) *Artifact {
+	return &Artifact{
+		FileName:    file.Filename,
+		FileHash:    file.Hashes["sha512"],
+		DownloadUrl: file.URL,
+		Dest:        dest,
+	}
+}
+
+func getPrimaryFile(files []api.ModrinthFile) *api.ModrinthFile {
+	for i := range files {
+		if files[i].Primary {
+			return &files[i]
+		}
+	}
+	return nil
+}
+
+func findFileByName(files []api.ModrinthFile, name string) *api.ModrinthFile {
+	for i := range files {
+		if strings.EqualFold(files[i].Filename, name) {
+			return &files[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package source
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+)
+
+// releaseProject is a repository whose releases have jar assets (GitHub, GitLab, Gitea, ...)
+type releaseProject struct {
+	baseUrl            string
+	repo               string
+	assetPattern       string
+	includePrereleases bool
+	token              string
+}
+
+// newReleaseProject reads the repository from the repoKey, the optional "asset" glob pattern (defaults to "*.jar"),
+// "includePrereleases" and "token" (defaults to the tokenEnv environment variable)
+func newReleaseProject(meta map[string]any, repoKey, tokenEnv string) (*releaseProject, error) {
+	repo, ok := stringMeta(meta, repoKey)
+	if !ok || !strings.Contains(repo, "/") {
+		return nil, fmt.Errorf("%s not found or not in \"owner/name\" format", repoKey)
+	}
+
+	project := &releaseProject{
+		repo:               repo,
+		assetPattern:       "*.jar",
+		includePrereleases: boolMeta(meta, "includePrereleases"),
+		token:              os.Getenv(tokenEnv),
+	}
+	if assetPattern, ok := stringMeta(meta, "asset"); ok {
+		project.assetPattern = assetPattern
+	}
+	if token, ok := stringMeta(meta, "token"); ok {
+		project.token = token
+	}
+	return project, nil
+}
+
+// releaseChannel maps the pre-release flag of a release to a release channel
+func releaseChannel(prerelease bool) string {
+	if prerelease {
+		return ChannelBeta
+	}
+	return ChannelRelease
+}
+
+// instanceHeaders returns the authorization headers for a download, but only if it is hosted on the instance itself.
+// Release links may point anywhere (e.g. a CDN), which must never receive the token. Links on the instance may still
+// redirect elsewhere, so the headers must only carry the token in Authorization, which isn't sent across hosts.
+func (p *releaseProject) instanceHeaders(downloadUrl string, headers map[string]string) map[string]string {
+	instance, err := url.Parse(p.baseUrl)
+	if err != nil {
+		return nil
+	}
+	download, err := url.Parse(downloadUrl)
+	if err != nil || !strings.EqualFold(download.Host, instance.Host) {
+		return nil
+	}
+	return headers
+}
+
+// matchesAsset reports whether an asset name matches the glob pattern, ignoring source and javadoc jars
+func (p *releaseProject) matchesAsset(name string) bool {
+	if strings.HasSuffix(name, "-sources.jar") || strings.HasSuffix(name, "-javadoc.jar") {
+		return false
+	}
+	matched, _ := path.Match(p.assetPattern, name)
+	return matched
+}
@@ -0,0 +1,247 @@
+package source
+
+import (
+	"encoding/json"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/SKevo18/server_updater/manifest"
+)
+
+// Project is a project resolved by a source
+type Project struct {
+	// Unique ID of the project within its source, used to deduplicate dependencies
+	ID string
+
+	// Human readable name of the project
+	Name string
+
+	// Human readable ID of the project (e.g. the Modrinth slug), empty if the source has none
+	Slug string
+
+	// Source specific project data (e.g. *api.ModrinthProject)
+	Data any
+}
+
+// Version is a version of a project
+type Version struct {
+	// Source specific version ID
+	ID string
+
+	// Version number, matched against the wanted version and filled into "{version}"
+	Number string
+
+	// Other names the version can be wanted by (e.g. "@lastStableBuild")
+	Aliases []string
+
+	// Release channel of the version (ChannelRelease, ChannelBeta or ChannelAlpha), empty if the source doesn't know
+	Channel string
+
+	// Source specific version data (e.g. *api.ModrinthVersion)
+	Data any
+}
+
+// Artifact is a file that is downloaded for a version
+type Artifact struct {
+	FileName    string
+	FileHash    string
+	DownloadUrl string
+
+	// Directory relative to the dependency type directory (e.g. "Typewriter/extensions"), empty for the directory itself
+	Dest string
+
+	// Additional headers to download the file with (e.g. authorization)
+	DownloadHeaders map[string]string
+}
+
+// Source resolves dependencies defined by a "source.<name>" metadata block
+type Source interface {
+	// ResolveProject resolves the project from the source metadata of a dependency
+	ResolveProject(dep *manifest.Dependency, meta map[string]any) (*Project, error)
+
+	// ListVersions lists the versions of a project, newest first.
+	// Versions that aren't compatible with the server are left out, unless incompatible is set.
+	ListVersions(project *Project, server manifest.Server, incompatible bool) ([]*Version, error)
+
+	// Artifacts returns the files to download for a version. The first artifact is the dependency itself.
+	Artifacts(dep *manifest.Dependency, project *Project, version *Version, server manifest.Server) ([]*Artifact, error)
+
+	// Dependencies returns the dependencies of a version, with their relation to it (required, optional or embedded)
+	Dependencies(project *Project, version *Version, server manifest.Server, incompatible bool) ([]*manifest.Dependency, error)
+}
+
+// Incompatibility is a project, or a single version of it, that can't be installed together with a version
+type Incompatibility struct {
+	// Project ID, empty if only the version ID is known
+	ProjectId string
+
+	// Version ID, empty if all versions of the project are incompatible
+	VersionId string
+}
+
+// IncompatibilitySource is implemented by sources whose versions declare incompatible projects
+type IncompatibilitySource interface {
+	// Incompatibilities returns the projects that are declared incompatible with a version
+	Incompatibilities(project *Project, version *Version) ([]Incompatibility, error)
+}
+
+// Authenticator is implemented by sources whose downloads may need authorization. The headers are rebuilt
+// from the source metadata, so that secrets never end up in the lockfile.
+type Authenticator interface {
+	// DownloadHeaders returns the headers to download a URL of the dependency with, nil if none are needed
+	DownloadHeaders(meta map[string]any, downloadUrl string) (map[string]string, error)
+}
+
+// VersionFetcher is implemented by sources that can fetch a version by its ID, even if it isn't listed
+type VersionFetcher interface {
+	// FetchVersion fetches a version of a project by its source specific version ID
+	FetchVersion(project *Project, id string) (*Version, error)
+}
+
+var sources = make(map[string]Source)
+
+// Register makes a source available under the "source.<name>" metadata key
+func Register(name string, source Source) {
+	sources[name] = source
+}
+
+// Get gets a registered source by name
+func Get(name string) (Source, bool) {
+	source, ok := sources[name]
+	return source, ok
+}
+
+// Release channels, from the most to the least stable
+const (
+	ChannelRelease = "release"
+	ChannelBeta    = "beta"
+	ChannelAlpha   = "alpha"
+)
+
+var channels = []string{ChannelRelease, ChannelBeta, ChannelAlpha}
+
+// IsChannel reports whether the channel is a known release channel, ignoring case. Empty means any channel.
+func IsChannel(channel string) bool {
+	return channel == "" || slices.Contains(channels, strings.ToLower(channel))
+}
+
+// SelectVersion resolves the wanted version string to a specific version. The wanted version is
+// "@latest", an exact version or alias, or a constraint (e.g. "^5.4") that selects the highest matching version.
+// "@latest" and constraints skip versions that are less stable than the minimum channel, exact versions are always allowed.
+func SelectVersion(versions []*Version, wantedVersion, channel string) *Version {
+	if wantedVersion == "@latest" {
+		return findVersion(versions, func(v *Version) bool { return isStableEnough(v.Channel, channel) })
+	}
+
+	if v := findVersion(versions, func(v *Version) bool { return isExactVersion(v, wantedVersion) }); v != nil {
+		return v
+	}
+
+	constraint, err := parseConstraint(wantedVersion)
+	if err != nil {
+		return nil
+	}
+
+	var selected *Version
+	var selectedVersion semVersion
+	for _, v := range versions {
+		version, ok := parseVersion(v.Number)
+		if !ok || !isStableEnough(v.Channel, channel) || !constraint.matches(version) {
+			continue
+		}
+		if selected == nil || version.compare(selectedVersion) > 0 {
+			selected, selectedVersion = v, version
+		}
+	}
+	return selected
+}
+
+// MatchesVersion reports whether a single version satisfies the wanted version string,
+// e.g. to stop paging through versions once one is found, or to check a version a dependent pins
+func MatchesVersion(v *Version, wantedVersion, channel string) bool {
+	if wantedVersion == "@latest" {
+		return isStableEnough(v.Channel, channel)
+	}
+	if isExactVersion(v, wantedVersion) {
+		return true
+	}
+
+	constraint, err := parseConstraint(wantedVersion)
+	if err != nil {
+		return false
+	}
+	version, ok := parseVersion(v.Number)
+	return ok && isStableEnough(v.Channel, channel) && constraint.matches(version)
+}
+
+// FindVersionById finds the version with the source specific version ID
+func FindVersionById(versions []*Version, id string) *Version {
+	return findVersion(versions, func(v *Version) bool { return v.ID == id })
+}
+
+func findVersion(versions []*Version, match func(*Version) bool) *Version {
+	for _, v := range versions {
+		if match(v) {
+			return v
+		}
+	}
+	return nil
+}
+
+func isExactVersion(v *Version, wantedVersion string) bool {
+	return v.Number == wantedVersion || slices.Contains(v.Aliases, wantedVersion)
+}
+
+// isStableEnough reports whether a version channel is at least as stable as the minimum channel.
+// Versions without a channel are treated as releases.
+func isStableEnough(versionChannel, minChannel string) bool {
+	if minChannel == "" || versionChannel == "" {
+		return true
+	}
+	return slices.Index(channels, strings.ToLower(versionChannel)) <= slices.Index(channels, strings.ToLower(minChannel))
+}
+
+// boolMeta gets the bool value of a key (case-insensitive) from source metadata, false if it isn't set
+func boolMeta(meta map[string]any, key string) bool {
+	for k, value := range meta {
+		if strings.EqualFold(k, key) {
+			b, _ := value.(bool)
+			return b
+		}
+	}
+	return false
+}
+
+// idMeta gets an ID (case-insensitive key) from source metadata, which can be written as a string or a whole number
+func idMeta(meta map[string]any, key string) (string, bool) {
+	for k, value := range meta {
+		if !strings.EqualFold(k, key) {
+			continue
+		}
+		switch id := value.(type) {
+		case string:
+			return id, true
+		case float64:
+			return strconv.FormatFloat(id, 'f', -1, 64), id == math.Trunc(id)
+		case json.Number:
+			return id.String(), true
+		}
+		return "", false
+	}
+	return "", false
+}
+
+// stringMeta gets the first string value of the given keys (case-insensitive) from source metadata
+func stringMeta(meta map[string]any, keys ...string) (string, bool) {
+	for key, value := range meta {
+		for _, wantedKey := range keys {
+			if strings.EqualFold(key, wantedKey) {
+				s, ok := value.(string)
+				return s, ok
+			}
+		}
+	}
+	return "", false
+}
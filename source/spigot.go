@@ -0,0 +1,81 @@
+package source
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/SKevo18/server_updater/api"
+	"github.com/SKevo18/server_updater/manifest"
+)
+
+type spigotSource struct{}
+
+func init() {
+	Register("spigot", spigotSource{})
+}
+
+// ResolveProject reads the SpigotMC "resourceId" (the number at the end of the resource URL), as a string or a number
+func (spigotSource) ResolveProject(dep *manifest.Dependency, meta map[string]any) (*Project, error) {
+	resourceId, ok := idMeta(meta, "resourceId")
+	if !ok {
+		return nil, fmt.Errorf("resourceId not found or not a string or number in spigot metadata")
+	}
+
+	resource, err := api.GetSpigetResource(resourceId)
+	if err != nil {
+		return nil, err
+	}
+	if resource.Premium {
+		return nil, fmt.Errorf("resource %s (%d) is premium and can't be downloaded through Spiget", resource.Name, resource.ID)
+	}
+	return &Project{ID: strconv.Itoa(resource.ID), Name: resource.Name, Data: resource}, nil
+}
+
+// ListVersions lists the versions of a resource. SpigotMC has no per-version compatibility information.
+func (spigotSource) ListVersions(project *Project, server manifest.Server, incompatible bool) ([]*Version, error) {
+	versions, err := api.GetSpigetVersions(project.Data.(*api.SpigetResource))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*Version, len(versions))
+	for i := range versions {
+		result[i] = &Version{ID: strconv.Itoa(versions[i].ID), Number: versions[i].Name, Data: &versions[i]}
+	}
+	return result, nil
+}
+
+func (spigotSource) Artifacts(dep *manifest.Dependency, project *Project, version *Version, server manifest.Server) ([]*Artifact, error) {
+	resource := project.Data.(*api.SpigetResource)
+	spigetVersion := version.Data.(*api.SpigetVersion)
+	fileName := spigotFileName(resource, spigetVersion)
+
+	if !resource.External {
+		if resource.File.Type != ".jar" {
+			return nil, fmt.Errorf("resource %s (%d) is a %s file, not a jar", resource.Name, resource.ID, resource.File.Type)
+		}
+		return []*Artifact{{FileName: fileName, DownloadUrl: api.GetSpigetDownloadUrl(resource, spigetVersion)}}, nil
+	}
+
+	// External resources only link to the latest version, which can be followed if it's a direct jar link
+	externalUrl, err := url.Parse(resource.File.ExternalURL)
+	if err != nil || !strings.HasSuffix(strings.ToLower(externalUrl.Path), ".jar") {
+		return nil, fmt.Errorf("resource %s (%d) is hosted externally at %s and has to be downloaded manually", resource.Name, resource.ID, resource.File.ExternalURL)
+	}
+	if spigetVersion.ID != resource.Version.ID {
+		return nil, fmt.Errorf("resource %s (%d) is hosted externally, only its latest version can be downloaded", resource.Name, resource.ID)
+	}
+	return []*Artifact{{FileName: fileName, DownloadUrl: resource.File.ExternalURL}}, nil
+}
+
+func (spigotSource) Dependencies(project *Project, version *Version, server manifest.Server, incompatible bool) ([]*manifest.Dependency, error) {
+	return nil, nil
+}
+
+// spigotFileName builds a file name, as Spiget doesn't provide the original one
+func spigotFileName(resource *api.SpigetResource, version *api.SpigetVersion) string {
+	name := strings.NewReplacer(" ", "-", "/", "-", "\\", "-").Replace(fmt.Sprintf("%s-%s", resource.Name, version.Name))
+	return name + ".jar"
+}
@@ -0,0 +1,85 @@
+package source
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/SKevo18/server_updater/manifest"
+)
+
+type urlSource struct{}
+
+type staticProject struct {
+	location      string
+	wantedVersion string
+	fileHash      string
+}
+
+func init() {
+	Register("url", urlSource{})
+}
+
+// ResolveProject reads the "url", which can contain "{version}" to be replaced with the wanted version,
+// and an optional expected "sha256" or "sha512" hash
+func (urlSource) ResolveProject(dep *manifest.Dependency, meta map[string]any) (*Project, error) {
+	rawUrl, ok := stringMeta(meta, "url")
+	if !ok {
+		return nil, fmt.Errorf("url not found or not a string in url metadata")
+	}
+
+	project, err := newStaticProject(rawUrl, dep, meta)
+	if err != nil {
+		return nil, err
+	}
+	return &Project{ID: rawUrl, Name: path.Base(rawUrl), Data: project}, nil
+}
+
+func (urlSource) ListVersions(project *Project, server manifest.Server, incompatible bool) ([]*Version, error) {
+	return project.Data.(*staticProject).versions(), nil
+}
+
+func (urlSource) Artifacts(dep *manifest.Dependency, project *Project, version *Version, server manifest.Server) ([]*Artifact, error) {
+	staticProject := project.Data.(*staticProject)
+	downloadUrl := staticProject.resolve(version)
+
+	fileName := path.Base(downloadUrl)
+	if parsedUrl, err := url.Parse(downloadUrl); err == nil {
+		fileName = path.Base(parsedUrl.Path)
+	}
+	return []*Artifact{{FileName: fileName, FileHash: staticProject.fileHash, DownloadUrl: downloadUrl}}, nil
+}
+
+func (urlSource) Dependencies(project *Project, version *Version, server manifest.Server, incompatible bool) ([]*manifest.Dependency, error) {
+	return nil, nil
+}
+
+// newStaticProject creates a project for a location with a single, user defined version
+func newStaticProject(location string, dep *manifest.Dependency, meta map[string]any) (*staticProject, error) {
+	if strings.Contains(location, "{version}") && dep.WantedVersion == "@latest" {
+		return nil, fmt.Errorf("%s contains {version}, so an exact version has to be wanted instead of @latest", location)
+	}
+
+	project := &staticProject{location: location, wantedVersion: dep.WantedVersion}
+	for _, key := range []string{"sha512", "sha256"} {
+		if fileHash, ok := stringMeta(meta, key); ok {
+			project.fileHash = fileHash
+			break
+		}
+	}
+	return project, nil
+}
+
+// versions returns the only version of a static project. "@latest" resolves to an unnamed version.
+func (p *staticProject) versions() []*Version {
+	if p.wantedVersion == "@latest" {
+		return []*Version{{}}
+	}
+	return []*Version{{ID: p.wantedVersion, Number: p.wantedVersion}}
+}
+
+// resolve fills the version into the location
+func (p *staticProject) resolve(version *Version) string {
+	return strings.ReplaceAll(p.location, "{version}", version.Number)
+}